@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// assignCommand assigns an issue to a user.
+func assignCommand() *cli.Command {
+	return &cli.Command{
+		Name:         "assign",
+		Usage:        "Assign an issue to a user",
+		ArgsUsage:    "<issue-id> <user-login>",
+		BashComplete: completeIssueID,
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 2 {
+				return fmt.Errorf("expected issue id and user login.")
+			}
+
+			id, err := parseIssueID(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			return rmc.AssignIssue(id, c.Args().Get(1))
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
+
+// attachCommand uploads a file and attaches it to an issue.
+func attachCommand() *cli.Command {
+	return &cli.Command{
+		Name:         "attach",
+		Usage:        "Upload a file and attach it to an issue",
+		ArgsUsage:    "<issue-id> <path>",
+		BashComplete: completeIssueID,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "description",
+				Usage: "Note added to the issue together with the attachment",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 2 {
+				return fmt.Errorf("expected issue id and file path.")
+			}
+
+			id, err := parseIssueID(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+
+			path := c.Args().Get(1)
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			token, err := rmc.UploadAttachment(path, f)
+			if err != nil {
+				return err
+			}
+
+			return rmc.AttachFile(id, token, path, c.String("description"))
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/b1tray3r/go/internal/redmine"
+	"github.com/spf13/viper"
+)
+
+var (
+	slugInvalid = regexp.MustCompile(`[^a-z0-9-]+`)
+	slugHyphens = regexp.MustCompile(`-{2,}`)
+)
+
+// slugify converts the given subject to a lowercase, hyphen separated string
+// containing only alphanumeric characters.
+func slugify(subject string) string {
+	slug := strings.ToLower(subject)
+	slug = strings.ReplaceAll(slug, " ", "-")
+	slug = slugInvalid.ReplaceAllString(slug, "")
+	slug = slugHyphens.ReplaceAllString(slug, "-")
+
+	return strings.Trim(slug, "-")
+}
+
+// showBranch prints a suggested git branch name for the issue with the given id.
+// The format is configured by rmi.branch.format using %d for the ID and %s for the slug.
+func showBranch(rmc *redmine.Client, param string) error {
+	id, err := parseIssueID(param)
+	if err != nil {
+		return err
+	}
+	i, err := rmc.GetIssue(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(viper.GetString("rmi.branch.format")+"\n", i.ID, slugify(i.Subject))
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+// closeCommand closes an issue with an optional comment.
+func closeCommand() *cli.Command {
+	return &cli.Command{
+		Name:         "close",
+		Usage:        "Close an issue",
+		ArgsUsage:    "<issue-id>",
+		BashComplete: completeIssueID,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "message",
+				Aliases: []string{"m"},
+				Usage:   "Comment added to the issue, use - to read from stdin",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("expected issue id not given as first param.")
+			}
+
+			id, err := parseIssueID(c.Args().First())
+			if err != nil {
+				return err
+			}
+
+			message, err := readInput(c.String("message"))
+			if err != nil {
+				return err
+			}
+
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			return rmc.CloseIssue(id, message)
+		},
+	}
+}
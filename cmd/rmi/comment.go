@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/b1tray3r/go/internal/redmine"
+)
+
+const editorTemplate = `
+# Please enter the comment for the issue. Lines starting
+# with '#' will be ignored, and an empty comment aborts.
+`
+
+// editComment opens $EDITOR with a temporary file and returns the content without comment lines.
+func editComment() (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file, err := os.CreateTemp("", "rmi-comment-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(editorTemplate); err != nil {
+		file.Close()
+		return "", err
+	}
+	file.Close()
+
+	cmd := exec.Command(editor, file.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+
+	dat, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(string(dat), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// postComment posts a comment to the issue with the given id. The body is taken from
+// the message, stdin if it is a pipe or composed in $EDITOR as last resort.
+func postComment(rmc *redmine.Client, param, message string, internal bool) error {
+	id, err := parseIssueID(param)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case message != "":
+	case stdinIsPipe():
+		dat, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read from stdin: %w", err)
+		}
+		message = string(dat)
+	default:
+		if message, err = editComment(); err != nil {
+			return err
+		}
+	}
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return fmt.Errorf("aborting due to empty comment.")
+	}
+
+	return rmc.WriteComment(id, message, internal)
+}
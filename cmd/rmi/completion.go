@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// completionShellEnv tells the completion which shell asks, descriptions are only shown by zsh and fish.
+const completionShellEnv = "RMI_COMPLETION_SHELL"
+
+// bashCompletion is the bash completion script of urfave/cli for rmi.
+const bashCompletion = `_rmi_bash_autocomplete() {
+  local cur opts
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  if [[ "$cur" == "-"* ]]; then
+    opts=$(${COMP_WORDS[@]:0:$COMP_CWORD} ${cur} --generate-bash-completion 2>/dev/null)
+  else
+    opts=$(${COMP_WORDS[@]:0:$COMP_CWORD} --generate-bash-completion 2>/dev/null)
+  fi
+  COMPREPLY=($(compgen -W "${opts}" -- ${cur}))
+  return 0
+}
+
+complete -o bashdefault -o default -o nospace -F _rmi_bash_autocomplete rmi
+`
+
+// zshCompletion is the zsh completion script of urfave/cli for rmi.
+const zshCompletion = `#compdef rmi
+
+_rmi_zsh_autocomplete() {
+  local -a opts
+  local cur
+  cur=${words[-1]}
+  if [[ "$cur" == "-"* ]]; then
+    opts=("${(@f)$(` + completionShellEnv + `=zsh ${words[@]:0:#words[@]-1} ${cur} --generate-bash-completion 2>/dev/null)}")
+  else
+    opts=("${(@f)$(` + completionShellEnv + `=zsh ${words[@]:0:#words[@]-1} --generate-bash-completion 2>/dev/null)}")
+  fi
+
+  if [[ "${opts[1]}" != "" ]]; then
+    _describe 'values' opts
+  else
+    _files
+  fi
+}
+
+compdef _rmi_zsh_autocomplete rmi
+`
+
+// fishIssueCompletion completes the issue IDs of the app, the commands are part of ToFishCompletion.
+const fishIssueCompletion = `complete -c rmi -f -n '__fish_use_subcommand' -a '(env ` + completionShellEnv + `=fish rmi --generate-bash-completion 2>/dev/null)'
+`
+
+// printSuggestion writes a completion value with its description if the shell shows them.
+func printSuggestion(w io.Writer, value, description string) {
+	switch os.Getenv(completionShellEnv) {
+	case "zsh":
+		fmt.Fprintf(w, "%s:%s\n", value, strings.ReplaceAll(description, ":", `\:`))
+	case "fish":
+		fmt.Fprintf(w, "%s\t%s\n", value, description)
+	default:
+		fmt.Fprintln(w, value)
+	}
+}
+
+// printIssueSuggestions writes the IDs of the open issues assigned to the current user.
+// Nothing is written if redmine can not be reached, completion must not print errors.
+func printIssueSuggestions(w io.Writer) {
+	rmc, err := newClient()
+	if err != nil {
+		return
+	}
+
+	issues, err := rmc.ListMyIssues()
+	if err != nil {
+		return
+	}
+
+	for _, i := range issues {
+		printSuggestion(w, strconv.FormatInt(i.ID, 10), i.Subject)
+	}
+}
+
+// completeApp suggests the commands and the issue IDs for the first argument of rmi.
+func completeApp(c *cli.Context) {
+	if c.NArg() == 0 {
+		for _, command := range c.App.VisibleCommands() {
+			for _, name := range command.Names() {
+				printSuggestion(c.App.Writer, name, command.Usage)
+			}
+		}
+	}
+
+	printIssueSuggestions(c.App.Writer)
+}
+
+// completeIssueID suggests the issue IDs for the first argument of a command.
+func completeIssueID(c *cli.Context) {
+	if c.NArg() > 0 {
+		return
+	}
+
+	printIssueSuggestions(c.App.Writer)
+}
+
+// completionCommand prints the completion script of the given shell.
+func completionCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "completion",
+		Usage:     "Print the shell completion script, e.g. eval \"$(rmi completion zsh)\"",
+		ArgsUsage: "bash | zsh | fish",
+		Action: func(c *cli.Context) error {
+			switch c.Args().First() {
+			case "bash":
+				_, err := fmt.Fprint(c.App.Writer, bashCompletion)
+				return err
+			case "zsh":
+				_, err := fmt.Fprint(c.App.Writer, zshCompletion)
+				return err
+			case "fish":
+				script, err := c.App.ToFishCompletion()
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Fprint(c.App.Writer, script+fishIssueCompletion)
+				return err
+			default:
+				return fmt.Errorf("expected shell bash, zsh or fish.")
+			}
+		},
+	}
+}
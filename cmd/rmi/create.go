@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/b1tray3r/go/internal/redmine"
+	"github.com/urfave/cli/v2"
+)
+
+// readInput returns the given value or reads the content from stdin if the value is "-".
+func readInput(value string) (string, error) {
+	if value != "-" {
+		return value, nil
+	}
+
+	dat, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from stdin: %w", err)
+	}
+
+	return string(dat), nil
+}
+
+// createCommand creates a new issue in redmine.
+func createCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "create",
+		Usage: "Create a new issue",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "project",
+				Usage:    "Project identifier or ID",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "subject",
+				Usage:    "Subject of the issue",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "description",
+				Usage: "Description of the issue, use - to read from stdin",
+			},
+			&cli.StringFlag{
+				Name:  "assignee",
+				Usage: "Login of the user the issue will be assigned to",
+			},
+			formatFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			description, err := readInput(c.String("description"))
+			if err != nil {
+				return err
+			}
+
+			var opts []redmine.IssueOption
+			if assignee := c.String("assignee"); assignee != "" {
+				user, err := rmc.GetUserByLogin(assignee)
+				if err != nil {
+					return err
+				}
+				opts = append(opts, redmine.WithAssignee(user.ID))
+			}
+
+			i, err := rmc.CreateIssue(
+				c.String("project"),
+				c.String("subject"),
+				description,
+				opts...,
+			)
+			if err != nil {
+				return err
+			}
+			// the dry run printed the payload, the issue has no ID to link to
+			if rmc.Dry {
+				return nil
+			}
+
+			if format := outputFormat(c); format != "markdown" {
+				return printIssue(os.Stdout, format, i)
+			}
+
+			fmt.Printf("%s/issues/%d\n", rmc.URL, i.ID)
+
+			return nil
+		},
+	}
+}
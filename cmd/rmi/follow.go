@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/b1tray3r/go/internal/redmine"
+	rm "github.com/nixys/nxs-go-redmine/v5"
+)
+
+// ANSI escape sequences used by watchIssue.
+const (
+	clearScreen = "\033[H\033[2J"
+	highlight   = "\033[1;33m"
+	resetColor  = "\033[0m"
+)
+
+// assigneeName returns the name of the assignee of the issue or "nobody".
+func assigneeName(i *rm.IssueObject) string {
+	if i.AssignedTo == nil {
+		return "nobody"
+	}
+
+	return i.AssignedTo.Name
+}
+
+// issueChanges returns the highlighted status and assignee changes between prev and cur.
+func issueChanges(prev, cur *rm.IssueObject) []string {
+	if prev == nil {
+		return nil
+	}
+
+	changes := make([]string, 0)
+	if prev.Status.Name != cur.Status.Name {
+		changes = append(changes, fmt.Sprintf("%sStatus: %s -> %s%s", highlight, prev.Status.Name, cur.Status.Name, resetColor))
+	}
+	if assigneeName(prev) != assigneeName(cur) {
+		changes = append(changes, fmt.Sprintf("%sAssignee: %s -> %s%s", highlight, assigneeName(prev), assigneeName(cur), resetColor))
+	}
+
+	return changes
+}
+
+// watchIssue renders the issue in the given format every interval until Ctrl-C is pressed.
+// Changes of the status and the assignee since the previous fetch are highlighted.
+func watchIssue(rmc *redmine.Client, param, format string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive.")
+	}
+
+	id, err := parseIssueID(param)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev *rm.IssueObject
+	for {
+		i, err := rmc.GetIssue(id)
+
+		fmt.Print(clearScreen)
+		fmt.Printf("Last updated: %s (every %s, Ctrl-C to stop)\n", time.Now().Format("2006-01-02 15:04:05"), interval)
+		if err != nil {
+			fmt.Printf("%serror: %v%s\n", highlight, err, resetColor)
+		} else {
+			for _, change := range issueChanges(prev, i) {
+				fmt.Println(change)
+			}
+			fmt.Println()
+
+			if err := printIssue(os.Stdout, format, i); err != nil {
+				return err
+			}
+			prev = i
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	rm "github.com/nixys/nxs-go-redmine/v5"
+	"github.com/urfave/cli/v2"
+)
+
+// filterByProject returns all issues which belong to the given project name or ID.
+func filterByProject(issues []rm.IssueObject, project string) []rm.IssueObject {
+	if project == "" {
+		return issues
+	}
+
+	filtered := make([]rm.IssueObject, 0)
+	for _, i := range issues {
+		if strings.EqualFold(i.Project.Name, project) || strconv.FormatInt(i.Project.ID, 10) == project {
+			filtered = append(filtered, i)
+		}
+	}
+
+	return filtered
+}
+
+// listCommand lists the open issues assigned to the current user.
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List open issues assigned to you",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "project",
+				Usage: "Only list issues of the given project name or ID",
+			},
+			formatFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			issues, err := rmc.ListMyIssues()
+			if err != nil {
+				return err
+			}
+			issues = filterByProject(issues, c.String("project"))
+
+			return printIssues(os.Stdout, outputFormat(c), issues)
+		},
+	}
+}
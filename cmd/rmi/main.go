@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"regexp"
 	"strconv"
@@ -9,11 +10,14 @@ import (
 	"time"
 
 	"github.com/b1tray3r/go/internal/redmine"
-	md "github.com/nao1215/markdown"
 	"github.com/sanity-io/litter"
 	"github.com/spf13/viper"
+	"github.com/urfave/cli/v2"
 )
 
+// deprecatedRedmineURL is the redmine instance of issue links if rmi.redmine.url is not set.
+const deprecatedRedmineURL = "https://projects.sdzecom.de"
+
 func setupConfig() {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yml")
@@ -29,6 +33,10 @@ func setupConfig() {
 	replacer := strings.NewReplacer(".", "_")
 	viper.SetEnvKeyReplacer(replacer)
 
+	viper.SetDefault("rmi.branch.format", "issue/%d-%s")
+	// issue IDs are written like #123 unless REDMINE_PREFIX or rmi.redmine.prefix is set
+	viper.SetDefault("rmi.redmine.prefix", "#")
+
 	// Read the config file
 	if err := viper.ReadInConfig(); !os.IsNotExist(err) {
 		if err != nil {
@@ -38,81 +46,76 @@ func setupConfig() {
 	}
 }
 
-func main() {
-	setupConfig()
+// newClient creates a redmine client based on the rmi configuration.
+func newClient() (*redmine.Client, error) {
+	return redmine.NewClientFromEnv()
+}
 
-	URL := viper.GetString("rmi.redmine.url")
-	KEY := viper.GetString("rmi.redmine.key")
+// parseIssueID converts the given parameter to an issue ID.
+func parseIssueID(param string) (int64, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(param, "#"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("you provided a parameter which can not be converted to int64.")
+	}
 
-	if URL == "" || KEY == "" {
-		panic("no credentials found in config or environment.")
+	return id, nil
+}
+
+// showIssue prints the issue with the given id in the given format.
+func showIssue(rmc *redmine.Client, param, format string) error {
+	id, err := parseIssueID(param)
+	if err != nil {
+		return err
+	}
+	i, err := rmc.GetIssue(id)
+	if err != nil {
+		return err
 	}
 
-	if len(os.Args) == 1 {
-		fmt.Fprintln(os.Stderr, "expected issue id not given as first param.")
-		os.Exit(1)
+	return printIssue(os.Stdout, format, i)
+}
+
+// issueLinkRegex builds the regex matching issue links of the redmine instance at the given URL.
+// Port and path prefix of the URL are respected, the scheme may be http or https.
+// Without URL the former default host is used.
+func issueLinkRegex(redmineURL string) (*regexp.Regexp, error) {
+	if redmineURL == "" {
+		fmt.Fprintln(os.Stderr, "DEPRECATED: rmi.redmine.url is not set, falling back to "+deprecatedRedmineURL)
+		redmineURL = deprecatedRedmineURL
 	}
 
-	rmc, err := redmine.NewClient(URL, KEY, "#", true)
+	u, err := url.Parse(redmineURL)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(1)
+		return nil, fmt.Errorf("invalid redmine url %s: %w", redmineURL, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid redmine url %s: missing host", redmineURL)
 	}
 
-	param := os.Args[1]
-	if param != "-c" {
-		id, err := strconv.ParseInt(param, 10, 64)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "you provided a parameter which can not be converted to int64.")
-			os.Exit(1)
-		}
-		i, err := rmc.GetIssue(id)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, err.Error())
-			os.Exit(1)
-		}
+	prefix := strings.TrimSuffix(u.Path, "/")
 
-		pn := strings.ReplaceAll(i.Project.Name, "-", "_")
-
-		md.NewMarkdown(os.Stdout).
-			HorizontalRule().
-			PlainTextf("redmine-project: %s", pn).
-			PlainTextf("redmine-reporter: %s", i.Author.Name).
-			PlainTextf("redmine-issue: \"%s/issues/%d\"", viper.GetString("rmi.redmine.url"), i.ID).
-			PlainTextf("redmine-last-update: %s", time.Now().Format("2006-01-02")).
-			HorizontalRule().
-			H1(i.Subject).
-			PlainText("\n").
-			PlainText(i.Description).
-			Build()
-
-		os.Exit(0)
-	}
+	return regexp.Compile(`https?://` + regexp.QuoteMeta(u.Host+prefix) + `/issues/\d+`)
+}
 
-	commit_msg_file := os.Args[2]
+// commitHook comments on every issue linked in the given commit message file.
+// https://adeboyedn.hashnode.dev/git-hooks-a-simple-guide#heading-post-commit
+func commitHook(rmc *redmine.Client, linkRegEx *regexp.Regexp, args cli.Args) error {
+	commit_msg_file := args.Get(0)
 	if _, err := os.Stat(commit_msg_file); err != nil && os.IsNotExist(err) {
-		fmt.Println("commit message file not found!")
-		os.Exit(1)
+		return fmt.Errorf("commit message file not found!")
 	}
 	dat, err := os.ReadFile(commit_msg_file)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	commit := string(dat)
 
-	// https://adeboyedn.hashnode.dev/git-hooks-a-simple-guide#heading-post-commit
-	if len(os.Args) < 4 {
-		fmt.Println("commit hash not found!")
-		os.Exit(1)
-	}
-	commit_hash := os.Args[3]
+	commit_hash := args.Get(1)
 	if commit_hash == "" {
-		fmt.Println("commit hash not found!")
-		os.Exit(1)
+		return fmt.Errorf("commit hash not found!")
 	}
 
-	linkRegEx := regexp.MustCompile(`https://projects.sdzecom.de/issues/\d+`)
-	match := linkRegEx.FindStringSubmatch(commit)
+	match := linkRegEx.FindAllString(commit, -1)
 
 	for _, m := range match {
 		m = strings.TrimSpace(m)
@@ -120,8 +123,7 @@ func main() {
 
 		issueID, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
 		if err != nil {
-			panic(err)
-			os.Exit(1)
+			return err
 		}
 
 		comment := `
@@ -138,9 +140,123 @@ Commit-Hash: %s
 			continue
 		}
 
-		if err := rmc.WriteComment(issueID, fmt.Sprintf(comment, commit, commit_hash)); err != nil {
-			fmt.Fprintln(os.Stderr, err.Error())
-			os.Exit(1)
+		if err := rmc.WriteComment(issueID, fmt.Sprintf(comment, commit, commit_hash), true); err != nil {
+			return err
 		}
 	}
+
+	return nil
+}
+
+func main() {
+	setupConfig()
+
+	app := &cli.App{
+		Name:                 "rmi",
+		Usage:                "Interact with redmine issues from the command line",
+		ArgsUsage:            "<issue-id>... | -c <commit-msg-file> <commit-hash>",
+		EnableBashCompletion: true,
+		BashComplete:         completeApp,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "c",
+				Usage: "Commit hook mode: comment on all issues linked in the commit message",
+			},
+			&cli.BoolFlag{
+				Name:  "branch",
+				Usage: "Print a suggested git branch name for the issue",
+			},
+			&cli.BoolFlag{
+				Name:  "comment",
+				Usage: "Post a comment to the issue, read from --message, stdin or $EDITOR",
+			},
+			&cli.StringFlag{
+				Name:    "message",
+				Aliases: []string{"m"},
+				Usage:   "Comment body used with --comment",
+			},
+			&cli.BoolFlag{
+				Name:  "internal",
+				Usage: "Post the comment as private note",
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Usage: "Render the issue again every --interval seconds until Ctrl-C",
+			},
+			&cli.IntFlag{
+				Name:  "interval",
+				Usage: "Seconds between the updates of --watch",
+				Value: 30,
+			},
+			formatFlag(),
+		},
+		Commands: []*cli.Command{
+			createCommand(),
+			statusCommand(),
+			assignCommand(),
+			closeCommand(),
+			watchCommand(),
+			listCommand(),
+			attachCommand(),
+			reportCommand(),
+			wikiCommand(),
+			relateCommand(),
+			versionCommand(),
+			newsCommand(),
+			completionCommand(),
+		},
+		Action: func(c *cli.Context) error {
+			ids := c.Args().Slice()
+			if c.NArg() == 0 && !c.Bool("comment") && stdinIsPipe() {
+				var err error
+				if ids, err = readIDs(os.Stdin); err != nil {
+					return err
+				}
+			}
+
+			if len(ids) == 0 {
+				return fmt.Errorf("expected issue id not given as first param.")
+			}
+
+			var linkRegEx *regexp.Regexp
+			if c.Bool("c") {
+				var err error
+				if linkRegEx, err = issueLinkRegex(redmine.URLFromEnv()); err != nil {
+					return err
+				}
+			}
+
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			if c.Bool("c") {
+				return commitHook(rmc, linkRegEx, c.Args())
+			}
+
+			if c.Bool("branch") {
+				return showBranch(rmc, ids[0])
+			}
+
+			if c.Bool("comment") {
+				return postComment(rmc, ids[0], c.String("message"), c.Bool("internal"))
+			}
+
+			if c.Bool("watch") {
+				return watchIssue(rmc, ids[0], outputFormat(c), time.Duration(c.Int("interval"))*time.Second)
+			}
+
+			if len(ids) > 1 {
+				return showIssues(rmc, ids, outputFormat(c))
+			}
+
+			return showIssue(rmc, ids[0], outputFormat(c))
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
 }
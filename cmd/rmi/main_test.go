@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIssueLinkRegex(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		matches   []string
+		unmatched []string
+	}{
+		{
+			name:      "host",
+			url:       "https://redmine.example.com",
+			matches:   []string{"https://redmine.example.com/issues/42", "http://redmine.example.com/issues/42"},
+			unmatched: []string{"https://other.example.com/issues/42", "https://redmineXexample.com/issues/42"},
+		},
+		{
+			name:      "trailing slash",
+			url:       "https://redmine.example.com/",
+			matches:   []string{"https://redmine.example.com/issues/42"},
+			unmatched: []string{"https://redmine.example.com//issues/42"},
+		},
+		{
+			name:      "port",
+			url:       "http://redmine.example.com:8080",
+			matches:   []string{"http://redmine.example.com:8080/issues/42"},
+			unmatched: []string{"http://redmine.example.com/issues/42", "http://redmine.example.com:8081/issues/42"},
+		},
+		{
+			name:      "path prefix",
+			url:       "https://example.com/redmine/",
+			matches:   []string{"https://example.com/redmine/issues/42"},
+			unmatched: []string{"https://example.com/issues/42", "https://example.com/other/issues/42"},
+		},
+		{
+			name:      "port and path prefix",
+			url:       "http://localhost:3000/tracker",
+			matches:   []string{"https://localhost:3000/tracker/issues/7"},
+			unmatched: []string{"http://localhost/tracker/issues/7", "ftp://localhost:3000/tracker/issues/7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := issueLinkRegex(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, link := range tt.matches {
+				if got := re.FindString("see " + link + " for details"); got != link {
+					t.Errorf("%s: found %q in %q", re, got, link)
+				}
+			}
+			for _, link := range tt.unmatched {
+				if re.MatchString(link) {
+					t.Errorf("%s matches %q", re, link)
+				}
+			}
+		})
+	}
+}
+
+func TestIssueLinkRegexInvalidURL(t *testing.T) {
+	for _, url := range []string{"redmine.example.com", "://redmine"} {
+		if _, err := issueLinkRegex(url); err == nil {
+			t.Errorf("issueLinkRegex(%q) succeeded", url)
+		}
+	}
+}
+
+func TestIssueLinkRegexDeprecatedFallback(t *testing.T) {
+	re, err := issueLinkRegex("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link := "https://projects.sdzecom.de/issues/42"; !re.MatchString(link) {
+		t.Errorf("%s does not match %q", re, link)
+	}
+}
+
+func TestNewClientDefaultPrefix(t *testing.T) {
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, ".config", "rmi"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".config", "rmi", "config.yml"), []byte("rmi:\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("REDMINE_URL", "https://redmine.example.com")
+	t.Setenv("REDMINE_API_KEY", "key")
+	setupConfig()
+
+	rmc, err := newClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rmc.Prefix != "#" {
+		t.Errorf("prefix = %q, want #", rmc.Prefix)
+	}
+
+	t.Setenv("REDMINE_PREFIX", "RM-")
+	if rmc, err = newClient(); err != nil {
+		t.Fatal(err)
+	}
+	if rmc.Prefix != "RM-" {
+		t.Errorf("prefix = %q, want the configured RM-", rmc.Prefix)
+	}
+}
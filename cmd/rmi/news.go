@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// newsPreviewLength is the number of description characters printed per news item.
+const newsPreviewLength = 80
+
+// newsPreview returns the first newsPreviewLength characters of the description on a single line.
+func newsPreview(description string) string {
+	preview := []rune(strings.Join(strings.Fields(description), " "))
+	if len(preview) <= newsPreviewLength {
+		return string(preview)
+	}
+
+	return string(preview[:newsPreviewLength]) + "…"
+}
+
+// newsCommand prints the latest news items of a project.
+func newsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "news",
+		Usage: "List the latest news of a project",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "project",
+				Usage:    "Project identifier or ID",
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Maximum number of news items, negative for all",
+				Value: 5,
+			},
+			formatFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			news, err := rmc.GetNewsItems(c.String("project"), c.Int("limit"))
+			if err != nil {
+				return err
+			}
+
+			return printNews(os.Stdout, outputFormat(c), news)
+		},
+	}
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/b1tray3r/go/internal/redmine"
+	md "github.com/nao1215/markdown"
+	rm "github.com/nixys/nxs-go-redmine/v5"
+	"github.com/spf13/viper"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// formatFlag defines the output format flag shared by the app and all subcommands.
+func formatFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "format",
+		Usage: "Output format: markdown, json, yaml or table",
+		Value: "markdown",
+	}
+}
+
+// outputFormat returns the format given to the closest command in the lineage.
+func outputFormat(c *cli.Context) string {
+	for _, ctx := range c.Lineage() {
+		if ctx.IsSet("format") {
+			return ctx.String("format")
+		}
+	}
+
+	return "markdown"
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeYAML writes v as YAML. The value is converted via JSON first
+// so the keys are the same as in the JSON output.
+func writeYAML(w io.Writer, v any) error {
+	dat, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var generic any
+	if err := json.Unmarshal(dat, &generic); err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	defer encoder.Close()
+	return encoder.Encode(generic)
+}
+
+// writeRows writes the header and rows as an aligned terminal table.
+func writeRows(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(header, "\t")))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// writeTable writes the issues as an aligned terminal table.
+func writeTable(w io.Writer, issues []rm.IssueObject) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSUBJECT\tSTATUS\tASSIGNEE\tUPDATED")
+	for _, i := range issues {
+		assignee := ""
+		if i.AssignedTo != nil {
+			assignee = i.AssignedTo.Name
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", i.ID, i.Subject, i.Status.Name, assignee, i.UpdatedOn)
+	}
+
+	return tw.Flush()
+}
+
+// printIssue writes a single issue in the given format.
+func printIssue(w io.Writer, format string, i *rm.IssueObject) error {
+	switch format {
+	case "json":
+		return writeJSON(w, i)
+	case "yaml":
+		return writeYAML(w, i)
+	case "table":
+		return writeTable(w, []rm.IssueObject{*i})
+	case "markdown":
+		pn := strings.ReplaceAll(i.Project.Name, "-", "_")
+
+		return md.NewMarkdown(w).
+			HorizontalRule().
+			PlainTextf("redmine-project: %s", pn).
+			PlainTextf("redmine-reporter: %s", i.Author.Name).
+			PlainTextf("redmine-issue: \"%s/issues/%d\"", viper.GetString("rmi.redmine.url"), i.ID).
+			PlainTextf("redmine-last-update: %s", time.Now().Format("2006-01-02")).
+			HorizontalRule().
+			H1(i.Subject).
+			PlainText("\n").
+			PlainText(i.Description).
+			Build()
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+}
+
+// printIssues writes a list of issues in the given format.
+func printIssues(w io.Writer, format string, issues []rm.IssueObject) error {
+	switch format {
+	case "json":
+		return writeJSON(w, issues)
+	case "yaml":
+		return writeYAML(w, issues)
+	case "table":
+		return writeTable(w, issues)
+	case "markdown":
+		rows := make([][]string, 0, len(issues))
+		for _, i := range issues {
+			rows = append(rows, []string{
+				strconv.FormatInt(i.ID, 10),
+				i.Project.Name,
+				i.Subject,
+				i.Status.Name,
+				i.UpdatedOn,
+			})
+		}
+
+		return md.NewMarkdown(w).
+			Table(md.TableSet{
+				Header: []string{"ID", "Project", "Subject", "Status", "Updated"},
+				Rows:   rows,
+			}).
+			Build()
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+}
+
+// printVersions writes the versions of a project in the given format.
+func printVersions(w io.Writer, format string, versions []redmine.VersionObject) error {
+	header := []string{"ID", "Name", "Status", "Due"}
+	rows := make([][]string, 0, len(versions))
+	for _, v := range versions {
+		rows = append(rows, []string{strconv.FormatInt(v.ID, 10), v.Name, v.Status, v.DueDate})
+	}
+
+	switch format {
+	case "json":
+		return writeJSON(w, versions)
+	case "yaml":
+		return writeYAML(w, versions)
+	case "table":
+		return writeRows(w, header, rows)
+	case "markdown":
+		return md.NewMarkdown(w).
+			Table(md.TableSet{Header: header, Rows: rows}).
+			Build()
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+}
+
+// printNews writes the news items in the given format, markdown as list with a preview of the description.
+func printNews(w io.Writer, format string, news []redmine.NewsObject) error {
+	switch format {
+	case "json":
+		return writeJSON(w, news)
+	case "yaml":
+		return writeYAML(w, news)
+	case "table":
+		rows := make([][]string, 0, len(news))
+		for _, n := range news {
+			date, _, _ := strings.Cut(n.CreatedOn, "T")
+			rows = append(rows, []string{strconv.FormatInt(n.ID, 10), n.Title, n.Author.Name, date})
+		}
+
+		return writeRows(w, []string{"ID", "Title", "Author", "Date"}, rows)
+	case "markdown":
+		items := make([]string, 0, len(news))
+		for _, n := range news {
+			date, _, _ := strings.Cut(n.CreatedOn, "T")
+			items = append(items, fmt.Sprintf("**%s** (%s, %s): %s", n.Title, n.Author.Name, date, newsPreview(n.Description)))
+		}
+
+		return md.NewMarkdown(w).
+			BulletList(items...).
+			Build()
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+}
+
+// printReport writes the time report in the given format. Markdown and table
+// contain one table per user, including the total, and one per issue.
+func printReport(w io.Writer, format string, report *redmine.TimeReport) error {
+	users := make([]string, 0, len(report.ByUser))
+	for user := range report.ByUser {
+		users = append(users, user)
+	}
+	slices.Sort(users)
+
+	userRows := make([][]string, 0, len(users))
+	for _, user := range users {
+		userRows = append(userRows, []string{user, formatHours(report.ByUser[user])})
+	}
+	userRows = append(userRows, []string{"Total", formatHours(report.TotalHours)})
+
+	issues := make([]int64, 0, len(report.ByIssue))
+	for issue := range report.ByIssue {
+		issues = append(issues, issue)
+	}
+	slices.Sort(issues)
+
+	issueRows := make([][]string, 0, len(issues))
+	for _, issue := range issues {
+		issueRows = append(issueRows, []string{"#" + strconv.FormatInt(issue, 10), formatHours(report.ByIssue[issue])})
+	}
+
+	switch format {
+	case "json":
+		return writeJSON(w, report)
+	case "yaml":
+		return writeYAML(w, report)
+	case "table":
+		if err := writeRows(w, []string{"User", "Hours"}, userRows); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+		return writeRows(w, []string{"Issue", "Hours"}, issueRows)
+	case "markdown":
+		return md.NewMarkdown(w).
+			H2("By user").
+			Table(md.TableSet{
+				Header: []string{"User", "Hours"},
+				Rows:   userRows,
+			}).
+			H2("By issue").
+			Table(md.TableSet{
+				Header: []string{"Issue", "Hours"},
+				Rows:   issueRows,
+			}).
+			Build()
+	default:
+		return fmt.Errorf("unknown format %s", format)
+	}
+}
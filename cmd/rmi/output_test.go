@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/b1tray3r/go/internal/redmine"
+)
+
+func TestPrintFormats(t *testing.T) {
+	printers := map[string]func(w io.Writer, format string) error{
+		"versions": func(w io.Writer, format string) error {
+			return printVersions(w, format, []redmine.VersionObject{{ID: 3, Name: "Sprint 3", Status: "open"}})
+		},
+		"news": func(w io.Writer, format string) error {
+			return printNews(w, format, []redmine.NewsObject{{ID: 7, Title: "Release", CreatedOn: "2024-01-15T10:00:00Z"}})
+		},
+		"report": func(w io.Writer, format string) error {
+			return printReport(w, format, &redmine.TimeReport{
+				TotalHours: 1.5,
+				ByUser:     map[string]float64{"alice": 1.5},
+				ByIssue:    map[int64]float64{42: 1.5},
+			})
+		},
+	}
+
+	for name, printFn := range printers {
+		for _, format := range []string{"markdown", "json", "yaml", "table"} {
+			t.Run(name+"/"+format, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := printFn(&buf, format); err != nil {
+					t.Fatal(err)
+				}
+				if strings.TrimSpace(buf.String()) == "" {
+					t.Error("empty output")
+				}
+			})
+		}
+
+		if err := printFn(io.Discard, "xml"); err == nil {
+			t.Errorf("%s: unknown format accepted", name)
+		}
+	}
+}
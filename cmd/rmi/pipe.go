@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/b1tray3r/go/internal/redmine"
+	rm "github.com/nixys/nxs-go-redmine/v5"
+)
+
+// stdinIsPipe reports whether stdin is connected to a pipe or a file instead of a terminal.
+func stdinIsPipe() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice == 0
+}
+
+// readIDs reads whitespace separated issue IDs from the given reader.
+func readIDs(r io.Reader) ([]string, error) {
+	ids := make([]string, 0)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		ids = append(ids, strings.TrimPrefix(scanner.Text(), "#"))
+	}
+
+	return ids, scanner.Err()
+}
+
+// fetchIssues fetches the issues with the given IDs concurrently.
+// The result keeps the order of the given IDs; failed fetches are nil
+// and their IDs are returned as second value.
+func fetchIssues(rmc *redmine.Client, ids []string) ([]*rm.IssueObject, []string) {
+	issues := make([]*rm.IssueObject, len(ids))
+	errs := make([]error, len(ids))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 5)
+	for n, param := range ids {
+		wg.Add(1)
+		go func(n int, param string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			id, err := parseIssueID(param)
+			if err != nil {
+				errs[n] = err
+				return
+			}
+
+			issues[n], errs[n] = rmc.GetIssue(id)
+		}(n, param)
+	}
+	wg.Wait()
+
+	failed := make([]string, 0)
+	for n, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", ids[n], err.Error())
+			failed = append(failed, ids[n])
+		}
+	}
+
+	return issues, failed
+}
+
+// showIssues prints all issues with the given IDs in the given format.
+func showIssues(rmc *redmine.Client, ids []string, format string) error {
+	issues, failed := fetchIssues(rmc, ids)
+
+	// markdown is a document per issue, all other formats are rendered as a list
+	found := make([]rm.IssueObject, 0, len(issues))
+	for _, i := range issues {
+		if i == nil {
+			continue
+		}
+		if format == "markdown" {
+			if err := printIssue(os.Stdout, format, i); err != nil {
+				return err
+			}
+			continue
+		}
+		found = append(found, *i)
+	}
+
+	if format != "markdown" {
+		if err := printIssues(os.Stdout, format, found); err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to fetch issues: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/b1tray3r/go/internal/redmine"
+	"github.com/urfave/cli/v2"
+)
+
+// relateCommand creates a relation between two issues or lists the relations of one issue.
+func relateCommand() *cli.Command {
+	return &cli.Command{
+		Name:         "relate",
+		Usage:        "Relate two issues, e.g. rmi relate 12 blocks 34, or list the relations of an issue",
+		ArgsUsage:    "<from-id> [<relation-type> <to-id>]",
+		BashComplete: completeIssueID,
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 && c.NArg() != 3 {
+				return fmt.Errorf("expected issue id, relation type and issue id.")
+			}
+
+			from, err := parseIssueID(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			if c.NArg() == 1 {
+				relations, err := rmc.GetIssueRelations(from)
+				if err != nil {
+					return err
+				}
+				for _, r := range relations {
+					fmt.Printf("#%d %s #%d\n", r.IssueID, r.RelationType, r.IssueToID)
+				}
+				return nil
+			}
+
+			to, err := parseIssueID(c.Args().Get(2))
+			if err != nil {
+				return err
+			}
+
+			return rmc.CreateRelation(from, to, redmine.RelationType(c.Args().Get(1)))
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+// formatHours formats hours with two decimals.
+func formatHours(hours float64) string {
+	return strconv.FormatFloat(hours, 'f', 2, 64)
+}
+
+// reportCommand prints the spent time of a project per user and issue.
+func reportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "report",
+		Usage: "Summarize the spent time of a project per user and issue",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "project",
+				Usage:    "Project identifier or ID",
+				Required: true,
+			},
+			&cli.TimestampFlag{
+				Name:     "from",
+				Usage:    "First day of the report, e.g. 2024-01-01",
+				Layout:   "2006-01-02",
+				Required: true,
+			},
+			&cli.TimestampFlag{
+				Name:     "to",
+				Usage:    "Last day of the report, e.g. 2024-01-31",
+				Layout:   "2006-01-02",
+				Required: true,
+			},
+			formatFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			from, to := c.Timestamp("from"), c.Timestamp("to")
+			if to.Before(*from) {
+				return fmt.Errorf("--to must not be before --from.")
+			}
+
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			report, err := rmc.GetSpentTimeReport(c.String("project"), *from, *to)
+			if err != nil {
+				return err
+			}
+
+			return printReport(os.Stdout, outputFormat(c), report)
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/b1tray3r/go/internal/redmine"
+	"github.com/urfave/cli/v2"
+)
+
+// statusCommand updates the status of an issue.
+func statusCommand() *cli.Command {
+	return &cli.Command{
+		Name:         "status",
+		Usage:        "Update the status of an issue",
+		ArgsUsage:    "<issue-id> <status-name>",
+		BashComplete: completeIssueID,
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 2 {
+				return fmt.Errorf("expected issue id and status name.")
+			}
+
+			id, err := parseIssueID(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			err = rmc.UpdateIssueStatus(id, c.Args().Get(1))
+			var ambiguous *redmine.AmbiguousStatusError
+			if errors.As(err, &ambiguous) {
+				fmt.Fprintln(os.Stderr, "the given status matches multiple statuses:")
+				for _, status := range ambiguous.Candidates {
+					fmt.Fprintf(os.Stderr, "  %d: %s\n", status.ID, status.Name)
+				}
+			}
+			if errors.Is(err, redmine.ErrStatusNotFound) {
+				if statuses, serr := rmc.GetIssueStatuses(); serr == nil {
+					fmt.Fprintln(os.Stderr, "available statuses:")
+					for _, status := range statuses {
+						fmt.Fprintf(os.Stderr, "  %d: %s\n", status.ID, status.Name)
+					}
+				}
+			}
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// versionProjectFlag is the project flag shared by the version subcommands.
+func versionProjectFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:     "project",
+		Usage:    "Project identifier or ID",
+		Required: true,
+	}
+}
+
+// versionCommand lists and creates the versions (milestones) of a project.
+func versionCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "version",
+		Usage: "List or create versions of a project",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List the versions of a project",
+				Flags: []cli.Flag{versionProjectFlag(), formatFlag()},
+				Action: func(c *cli.Context) error {
+					rmc, err := newClient()
+					if err != nil {
+						return err
+					}
+
+					versions, err := rmc.GetVersions(c.String("project"))
+					if err != nil {
+						return err
+					}
+
+					return printVersions(os.Stdout, outputFormat(c), versions)
+				},
+			},
+			{
+				Name:  "create",
+				Usage: "Create a version in a project",
+				Flags: []cli.Flag{
+					versionProjectFlag(),
+					&cli.StringFlag{
+						Name:     "name",
+						Usage:    "Name of the version, e.g. \"Sprint 3\"",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "description",
+						Usage: "Description of the version",
+					},
+					&cli.StringFlag{
+						Name:  "due",
+						Usage: "Due date of the version, e.g. 2024-02-01",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					due := c.String("due")
+					if due != "" {
+						if _, err := time.Parse("2006-01-02", due); err != nil {
+							return fmt.Errorf("due date must be a date like 2024-02-01: %w", err)
+						}
+					}
+
+					rmc, err := newClient()
+					if err != nil {
+						return err
+					}
+
+					return rmc.CreateVersion(c.String("project"), c.String("name"), c.String("description"), due)
+				},
+			},
+		},
+	}
+}
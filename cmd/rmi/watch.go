@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/b1tray3r/go/internal/redmine"
+	"github.com/urfave/cli/v2"
+)
+
+// watchCommand adds the current user to the watchers of an issue.
+func watchCommand() *cli.Command {
+	return &cli.Command{
+		Name:         "watch",
+		Usage:        "Watch an issue to get notified about its changes",
+		ArgsUsage:    "<issue-id>",
+		BashComplete: completeIssueID,
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("expected issue id not given as first param.")
+			}
+
+			id, err := parseIssueID(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+
+			rmc, err := newClient()
+			if err != nil {
+				return err
+			}
+
+			user, err := rmc.GetCurrentUser()
+			if err != nil {
+				return err
+			}
+
+			err = rmc.AddWatcher(id, user.ID)
+			if errors.Is(err, redmine.ErrAlreadyWatching) {
+				fmt.Printf("you are already watching #%d\n", id)
+				return nil
+			}
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
+
+// wikiFlags are the flags shared by the wiki subcommands.
+func wikiFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:     "project",
+			Usage:    "Project identifier or ID",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:     "title",
+			Usage:    "Title of the wiki page",
+			Required: true,
+		},
+	}
+}
+
+// readPage reads the wiki page content from stdin.
+func readPage() (string, error) {
+	if !stdinIsPipe() {
+		return "", fmt.Errorf("expected the page content on stdin.")
+	}
+
+	dat, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from stdin: %w", err)
+	}
+
+	return string(dat), nil
+}
+
+// wikiCommand creates and updates wiki pages from markdown read on stdin.
+func wikiCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "wiki",
+		Usage: "Create or update wiki pages, the content is read from stdin",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "create",
+				Usage: "Create a wiki page",
+				Flags: wikiFlags(),
+				Action: func(c *cli.Context) error {
+					content, err := readPage()
+					if err != nil {
+						return err
+					}
+
+					rmc, err := newClient()
+					if err != nil {
+						return err
+					}
+
+					return rmc.CreateWikiPage(c.String("project"), c.String("title"), content)
+				},
+			},
+			{
+				Name:  "update",
+				Usage: "Update a wiki page",
+				Flags: append(wikiFlags(), &cli.StringFlag{
+					Name:  "comment",
+					Usage: "Description of the change shown in the page history",
+				}),
+				Action: func(c *cli.Context) error {
+					content, err := readPage()
+					if err != nil {
+						return err
+					}
+
+					rmc, err := newClient()
+					if err != nil {
+						return err
+					}
+
+					return rmc.UpdateWikiPage(c.String("project"), c.String("title"), content, c.String("comment"))
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// fileChecksum returns the hex encoded SHA-256 of the file content.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteChecksumFile writes the checksums of all backups which were not removed to path.
+// The format is the one of sha256sum, so the backups can be checked with sha256sum -c.
+func (r *Rotator) WriteChecksumFile(path string) error {
+	var sb strings.Builder
+	for _, backup := range r.FoundFiles {
+		if backup.Checksum == "" || slices.Contains(r.Removed, backup.Name) {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s  %s\n", backup.Checksum, backup.Name)
+	}
+
+	if r.Dry {
+		fmt.Println("DryRun: write checksums to", path)
+		fmt.Print(sb.String())
+		return nil
+	}
+
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+// verifyChecksum compares the checksum computed by Read with the current content of the backup.
+func (r *Rotator) verifyChecksum(backup BackupFile) error {
+	if backup.Checksum == "" {
+		return nil
+	}
+
+	sum, err := fileChecksum(r.SourceDir + backup.Name)
+	if err != nil {
+		return err
+	}
+	if sum != backup.Checksum {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", backup.Checksum, sum)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/urfave/cli/v2"
+)
+
+// DatabaseConfig describes the rotation of a single database in the config file.
+// Retention counts which are not set fall back to the CLI flags.
+type DatabaseConfig struct {
+	Name        string `mapstructure:"name"`
+	Source      string `mapstructure:"source"`
+	Destination string `mapstructure:"destination"`
+
+	Keep       *int `mapstructure:"keep"`
+	KeepHourly *int `mapstructure:"keep-hourly"`
+	KeepDays   *int `mapstructure:"keep-days"`
+	KeepWeeks  *int `mapstructure:"keep-weeks"`
+	KeepMonths *int `mapstructure:"keep-months"`
+	KeepYears  *int `mapstructure:"keep-years"`
+}
+
+// setupConfig reads the settings which have no flag or whose flag is not given, e.g.
+// wls.rotator.hookTimeout, from the environment (WLS_ROTATOR_HOOKTIMEOUT) and the
+// YAML config file at path into the global viper instance. path may be empty.
+func setupConfig(path string) error {
+	viper.SetDefault("wls.rotator.hookTimeout", DefaultHookTimeout)
+	viper.AutomaticEnv()
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+
+	if path == "" {
+		return nil
+	}
+
+	viper.SetConfigFile(path)
+	viper.SetConfigType("yml")
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// hookTimeout returns the --hook-timeout flag or else the wls.rotator.hookTimeout setting.
+func hookTimeout(c *cli.Context) time.Duration {
+	if c.IsSet("hook-timeout") {
+		return c.Duration("hook-timeout")
+	}
+
+	return viper.GetDuration("wls.rotator.hookTimeout")
+}
+
+// readDatabases reads the databases from the config file at path read into v.
+//
+//	databases:
+//	  - name: shop
+//	    source: /backups/shop/
+//	    destination: /links/shop/
+//	    keep-days: 14
+func readDatabases(v *viper.Viper, path string) ([]DatabaseConfig, error) {
+	var dbs []DatabaseConfig
+	if err := v.UnmarshalKey("databases", &dbs); err != nil {
+		return nil, fmt.Errorf("failed to parse databases in %s: %w", path, err)
+	}
+
+	return dbs, nil
+}
+
+// apply overrides the rotator settings with the values set in the config.
+func (db DatabaseConfig) apply(r *Rotator) {
+	for _, v := range []struct {
+		src *int
+		dst *int
+	}{
+		{db.Keep, &r.Keep},
+		{db.KeepHourly, &r.KeepHourly},
+		{db.KeepDays, &r.KeepDays},
+		{db.KeepWeeks, &r.KeepWeeks},
+		{db.KeepMonths, &r.KeepMonths},
+		{db.KeepYears, &r.KeepYears},
+	} {
+		if v.src != nil {
+			*v.dst = *v.src
+		}
+	}
+}
+
+// rotateConfig runs a separate rotation for every database in the config file.
+// Errors are collected so a failing database does not stop the others.
+func rotateConfig(c *cli.Context, path string) error {
+	dbs, err := readDatabases(viper.GetViper(), path)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	results := make([]string, 0, len(dbs))
+	for _, db := range dbs {
+		fmt.Println("Rotating database:", db.Name)
+
+		rotator := newRotator(c, db.Source, db.Destination)
+		db.apply(rotator)
+
+		if err := rotate(c, rotator); err != nil {
+			failed++
+			results = append(results, fmt.Sprintf("%s: error: %v", db.Name, err))
+			continue
+		}
+		results = append(results, fmt.Sprintf("%s: ok, freed %s", db.Name, formatBytes(rotator.Stats().FreedBytes)))
+	}
+
+	fmt.Println("Summary:")
+	for _, result := range results {
+		fmt.Println(" ", result)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d databases failed", failed, len(dbs))
+	}
+
+	return nil
+}
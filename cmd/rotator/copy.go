@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// copyLink stores the backup at srcPath as real file at destPath. A hard link is used
+// if both are on the same filesystem, otherwise the backup is copied with atomicCopyFile.
+// If another tag of the backup was already copied, the copy is hard linked instead of copying again.
+func copyLink(srcPath, destPath string) error {
+	if existing := existingCopy(srcPath, destPath); existing != "" {
+		if err := os.Link(existing, destPath); err == nil {
+			return nil
+		}
+	}
+
+	err := os.Link(srcPath, destPath)
+	if errors.Is(err, syscall.EXDEV) {
+		return atomicCopyFile(srcPath, destPath)
+	}
+
+	return err
+}
+
+// existingCopy returns the path of a copy of srcPath stored with another tag
+// in the directory of destPath or an empty string if there is none.
+func existingCopy(srcPath, destPath string) string {
+	src, err := os.Stat(srcPath)
+	if err != nil {
+		return ""
+	}
+
+	dir := filepath.Dir(destPath)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), "-"+filepath.Base(srcPath)) {
+			continue
+		}
+		if info, err := file.Info(); err == nil && sameCopy(src, info) {
+			return filepath.Join(dir, file.Name())
+		}
+	}
+
+	return ""
+}
+
+// sameCopy reports whether dst is a complete copy of src. atomicCopyFile keeps the
+// modification time, so a copy has the size and the modification time of its source.
+func sameCopy(src, dst os.FileInfo) bool {
+	return dst.Mode().IsRegular() && dst.Size() == src.Size() && dst.ModTime().Equal(src.ModTime())
+}
+
+// atomicCopyFile copies src to a temporary file in the directory of dst, syncs it and
+// renames it to dst, so dst is either missing or complete even if the copy is interrupted.
+// The modification time of src is kept to detect complete copies in later runs, see sameCopy.
+//
+// Unlike a move, the source is kept: the rotator reads the retained backups from the source
+// on every run, a moved backup would vanish from the retention and never be removed.
+func atomicCopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// removing fails once the file is renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp.Name(), info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAtomicCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "2024-01-15T03-00-00.sql.gz")
+	writeBackup(t, dir, filepath.Base(src))
+	mtime := time.Date(2024, time.January, 15, 3, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(src, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "daily-2024-01-15T03-00-00.sql.gz")
+	if err := atomicCopyFile(src, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	srcInfo, _ := os.Stat(src)
+	dstInfo, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.SameFile(srcInfo, dstInfo) {
+		t.Error("expected a copy, got a link")
+	}
+	if !sameCopy(srcInfo, dstInfo) {
+		t.Errorf("copy has size %d and mtime %s, want %d and %s", dstInfo.Size(), dstInfo.ModTime(), srcInfo.Size(), srcInfo.ModTime())
+	}
+}
+
+func TestCopyLinkReusesExistingCopy(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "2024-01-15T03-00-00.sql.gz")
+	writeBackup(t, dir, filepath.Base(src))
+
+	// a copy of another tag, e.g. on another filesystem
+	daily := filepath.Join(dir, "daily-2024-01-15T03-00-00.sql.gz")
+	if err := atomicCopyFile(src, daily); err != nil {
+		t.Fatal(err)
+	}
+
+	weekly := filepath.Join(dir, "weekly-2024-01-15T03-00-00.sql.gz")
+	if err := copyLink(src, weekly); err != nil {
+		t.Fatal(err)
+	}
+
+	dailyInfo, _ := os.Stat(daily)
+	weeklyInfo, err := os.Stat(weekly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(dailyInfo, weeklyInfo) {
+		t.Error("expected the weekly copy to be a hard link of the daily copy")
+	}
+}
+
+func TestRotateKeepsCopies(t *testing.T) {
+	r := newTestRotator(t, "2024-01-15T03-00-00.sql.gz", "2024-01-16T03-00-00.sql.gz")
+	r.LinkMode = "copy"
+	r.KeepDays = 2
+
+	rotate := func() os.FileInfo {
+		t.Helper()
+		if _, err := r.Read(); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(r.DestinationDir + "daily-2024-01-16T03-00-00.sql.gz")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+
+	first := rotate()
+	if second := rotate(); !os.SameFile(first, second) {
+		t.Error("copy of a retained backup was replaced by the second run")
+	}
+}
+
+func TestDryRotateCopiesNothing(t *testing.T) {
+	r := newTestRotator(t, "2024-01-15T03-00-00.sql.gz", "2024-01-16T03-00-00.sql.gz")
+	r.LinkMode = "copy"
+	r.KeepDays = 1
+	r.Dry = true
+
+	// a link of the last run is kept in dry run mode
+	writeBackup(t, r.DestinationDir, "daily-2024-01-15T03-00-00.sql.gz")
+
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(r.DestinationDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "daily-2024-01-15T03-00-00.sql.gz" {
+		t.Errorf("destination = %v, want only the link of the last run", entries)
+	}
+	if _, err := os.Stat(r.SourceDir + "2024-01-15T03-00-00.sql.gz"); err != nil {
+		t.Errorf("backup removed in dry run mode: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Destination stores the links of the selected backups.
+type Destination interface {
+	// Put stores the content of r under name.
+	Put(name string, r io.Reader) error
+	// Delete removes name, missing names are ignored.
+	Delete(name string) error
+}
+
+// Lister is implemented by destinations which can list the stored names,
+// so links of tags a retained backup lost can be removed, see removeStaleLinks.
+type Lister interface {
+	List() ([]string, error)
+}
+
+// LocalDestination links the backups into a local directory.
+type LocalDestination struct {
+	Dir string
+
+	// Link creates the link at destPath pointing to srcPath, e.g. os.Symlink.
+	Link func(srcPath, destPath string) error
+	Dry  bool
+}
+
+// Put links the backup file r to name. Existing links are kept.
+// Readers which are no files are copied.
+func (d *LocalDestination) Put(name string, r io.Reader) error {
+	destPath := d.Dir + name
+	if _, err := os.Lstat(destPath); err == nil {
+		return nil
+	}
+
+	if d.Dry {
+		fmt.Println("DryRun: link", destPath)
+		return nil
+	}
+
+	if f, ok := r.(*os.File); ok {
+		return d.Link(f.Name(), destPath)
+	}
+
+	out, err := os.OpenFile(destPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// Delete removes the link name.
+func (d *LocalDestination) Delete(name string) error {
+	if d.Dry {
+		fmt.Println("DryRun: delete", d.Dir+name)
+		return nil
+	}
+
+	if err := os.RemoveAll(d.Dir + name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// destination returns the configured Destination or a LocalDestination for DestinationDir
+// creating links as configured by LinkMode.
+func (r *Rotator) destination() (Destination, error) {
+	if r.Destination != nil {
+		return r.Destination, nil
+	}
+
+	linkFn := os.Symlink
+	switch r.LinkMode {
+	case "", "symlink":
+		if r.RelativeLinks {
+			linkFn = relativeSymlink
+		}
+	case "hardlink":
+		if r.EntryType == "directory" {
+			return nil, fmt.Errorf("hard links are not supported for directories")
+		}
+		if err := sameFilesystem(r.SourceDir, r.DestinationDir); err != nil {
+			return nil, err
+		}
+		linkFn = os.Link
+	case "copy":
+		if r.EntryType == "directory" {
+			return nil, fmt.Errorf("copies are not supported for directories")
+		}
+		linkFn = copyLink
+	default:
+		return nil, fmt.Errorf("unknown link mode %s", r.LinkMode)
+	}
+
+	return &LocalDestination{Dir: r.DestinationDir, Link: linkFn, Dry: r.Dry}, nil
+}
+
+// remoteDestination returns the destination configured by the CLI flags
+// or nil if the backups are linked into the local destination directory.
+func remoteDestination(c *cli.Context, dry bool) (Destination, error) {
+	bucket, host := c.String("s3-bucket"), c.String("sftp-host")
+	switch {
+	case bucket != "" && host != "":
+		return nil, fmt.Errorf("--s3-bucket and --sftp-host can not be combined")
+	case bucket != "":
+		return NewS3Destination(c.Context, bucket, c.String("s3-prefix"), c.String("s3-region"), dry)
+	case host != "":
+		return NewSFTPDestination(host, c.String("sftp-user"), c.String("sftp-key"), c.String("sftp-path"), dry)
+	}
+
+	return nil, nil
+}
+
+// removeStaleLinks deletes the links of selected backups in dest whose tag is not linked anymore,
+// e.g. a backup which was the newest weekly one. Local destinations are cleared instead.
+func (r *Rotator) removeStaleLinks(dest Destination) error {
+	lister, ok := dest.(Lister)
+	if !ok {
+		return nil
+	}
+
+	names, err := lister.List()
+	if err != nil {
+		return err
+	}
+
+	selected := r.selectedTags()
+	linked := make(map[string]bool)
+	for name, tags := range selected {
+		for _, tag := range r.linkTags(tags) {
+			linked[tag+"-"+name] = true
+		}
+	}
+
+	for _, name := range names {
+		tag, backup, ok := strings.Cut(name, "-")
+		// links of removed backups are deleted by remove
+		if !ok || linked[name] || !slices.Contains(r.tagPriority(), tag) || selected[backup] == nil {
+			continue
+		}
+
+		if err := dest.Delete(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// memDestination stores the links in memory.
+type memDestination struct {
+	objects map[string][]byte
+}
+
+func (d *memDestination) Put(name string, r io.Reader) error {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	d.objects[name] = content
+
+	return nil
+}
+
+func (d *memDestination) Delete(name string) error {
+	delete(d.objects, name)
+	return nil
+}
+
+func (d *memDestination) List() ([]string, error) {
+	return slices.Sorted(maps.Keys(d.objects)), nil
+}
+
+func TestRemoveStaleLinks(t *testing.T) {
+	r := newTestRotator(t,
+		"2024-01-15T03-00-00.sql.gz",
+		"2024-01-16T03-00-00.sql.gz",
+		"2024-01-17T03-00-00.sql.gz",
+	)
+	dest := &memDestination{objects: map[string][]byte{}}
+	r.Destination = dest
+	r.KeepDays = 3
+	r.KeepWeeks = 1
+
+	rotate := func() {
+		t.Helper()
+		if _, err := r.Read(); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rotate()
+	if _, ok := dest.objects["weekly-2024-01-17T03-00-00.sql.gz"]; !ok {
+		t.Fatalf("weekly link missing, got %v", slices.Sorted(maps.Keys(dest.objects)))
+	}
+
+	// the new backup is the weekly one now, the oldest one is removed
+	writeBackup(t, r.SourceDir, "2024-01-18T03-00-00.sql.gz")
+	rotate()
+
+	want := []string{
+		"daily-2024-01-16T03-00-00.sql.gz",
+		"daily-2024-01-17T03-00-00.sql.gz",
+		"daily-2024-01-18T03-00-00.sql.gz",
+		"weekly-2024-01-18T03-00-00.sql.gz",
+	}
+	if got, _ := dest.List(); !slices.Equal(got, want) {
+		t.Errorf("links = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+// checkFreeSpace is a no-op, statfs is not available on this platform.
+func (r *Rotator) checkFreeSpace(dir string) error {
+	return nil
+}
@@ -0,0 +1,33 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// checkFreeSpace returns an error if the filesystem of dir has less
+// free bytes or inodes than the configured minimums.
+func (r *Rotator) checkFreeSpace(dir string) error {
+	if r.MinFreeBytes <= 0 && r.MinFreeInodes == 0 {
+		return nil
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return fmt.Errorf("failed to stat filesystem of %s: %w", dir, err)
+	}
+
+	freeBytes := uint64(stat.Bavail) * uint64(stat.Bsize)
+	if r.MinFreeBytes > 0 && freeBytes < uint64(r.MinFreeBytes) {
+		return fmt.Errorf("not enough free space on %s: %d bytes available, %d required", dir, freeBytes, r.MinFreeBytes)
+	}
+
+	freeInodes := uint64(stat.Ffree)
+	if r.MinFreeInodes > 0 && freeInodes < r.MinFreeInodes {
+		return fmt.Errorf("not enough free inodes on %s: %d available, %d required", dir, freeInodes, r.MinFreeInodes)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Export returns all found backups annotated with their selection, tags and age.
+func (r *Rotator) Export() []BackupFile {
+	tags := r.selectedTags()
+
+	files := make([]BackupFile, 0, len(r.FoundFiles))
+	for _, backup := range r.FoundFiles {
+		backup.Tags = tags[backup.Name]
+		backup.Selected = len(backup.Tags) > 0
+		backup.AgeHours = time.Since(backup.Time).Hours()
+		files = append(files, backup)
+	}
+
+	return files
+}
+
+// writeExport writes the exported backups as pretty printed JSON to path.
+func writeExport(path string, files []BackupFile) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(files)
+}
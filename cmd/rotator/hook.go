@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// DefaultHookTimeout is the maximum runtime of the post rotation hook.
+const DefaultHookTimeout = 60 * time.Second
+
+// shellCommand returns the command running line with sh or nil if line is empty.
+func shellCommand(line string) []string {
+	if line == "" {
+		return nil
+	}
+
+	return []string{"sh", "-c", line}
+}
+
+// runPostHook runs PostHookCmd with the stats of the rotation as environment variables
+// ROTATOR_KEPT, ROTATOR_REMOVED and ROTATOR_FREED_BYTES and prints its output.
+// Failures are only printed as warning so the hook does not fail the rotation.
+func (r *Rotator) runPostHook() {
+	if len(r.PostHookCmd) == 0 {
+		return
+	}
+
+	timeout := r.HookTimeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+
+	if r.Dry {
+		fmt.Println("DryRun: run post hook", r.PostHookCmd)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stats := r.Stats()
+	cmd := exec.CommandContext(ctx, r.PostHookCmd[0], r.PostHookCmd[1:]...)
+	cmd.Env = append(os.Environ(),
+		"ROTATOR_KEPT="+strconv.Itoa(stats.Kept),
+		"ROTATOR_REMOVED="+strconv.Itoa(stats.Removed),
+		"ROTATOR_FREED_BYTES="+strconv.FormatInt(stats.FreedBytes, 10),
+	)
+	// children of the shell may keep the output open after it was killed
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.CombinedOutput()
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		fmt.Println("post hook:", scanner.Text())
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		fmt.Printf("warning: post hook timed out after %s\n", timeout)
+	} else if err != nil {
+		fmt.Printf("warning: post hook failed: %v\n", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/urfave/cli/v2"
+)
+
+// failingDestination rejects every upload.
+type failingDestination struct{}
+
+func (failingDestination) Put(name string, r io.Reader) error {
+	return errors.New("upload failed")
+}
+
+func (failingDestination) Delete(name string) error {
+	return nil
+}
+
+func TestRotateSkipsHookOnLinkError(t *testing.T) {
+	r := newTestRotator(t,
+		"2024-01-16T03-00-00.sql.gz",
+		"2024-01-17T03-00-00.sql.gz",
+	)
+	r.Destination = failingDestination{}
+	r.KeepDays = 2
+
+	marker := filepath.Join(t.TempDir(), "hook-ran")
+	r.PostHookCmd = shellCommand("touch " + marker)
+
+	c := cli.NewContext(cli.NewApp(), flag.NewFlagSet("test", flag.ContinueOnError), nil)
+	if err := rotate(c, r); err == nil {
+		t.Fatal("rotate succeeded with a failing destination")
+	}
+
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("post hook ran after a failed rotation: %v", err)
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	t.Cleanup(viper.Reset)
+	if err := setupConfig(""); err != nil {
+		t.Fatal(err)
+	}
+
+	newContext := func(args ...string) *cli.Context {
+		t.Helper()
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		set.Duration("hook-timeout", 0, "")
+		if err := set.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cli.NewContext(cli.NewApp(), set, nil)
+	}
+
+	if got := hookTimeout(newContext()); got != DefaultHookTimeout {
+		t.Errorf("default timeout = %s, want %s", got, DefaultHookTimeout)
+	}
+
+	viper.Set("wls.rotator.hookTimeout", "5m")
+	if got := hookTimeout(newContext()); got != 5*time.Minute {
+		t.Errorf("configured timeout = %s, want 5m", got)
+	}
+	if got := hookTimeout(newContext("-hook-timeout", "10s")); got != 10*time.Second {
+		t.Errorf("flag timeout = %s, want 10s", got)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package main
+
+// sameFilesystem can not detect the device on this platform,
+// os.Link will report the error instead.
+func sameFilesystem(a, b string) error {
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// sameFilesystem returns an error if both paths are not located on the same device,
+// which is required to create hard links.
+func sameFilesystem(a, b string) error {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return err
+	}
+
+	statA, okA := infoA.Sys().(*syscall.Stat_t)
+	statB, okB := infoB.Sys().(*syscall.Stat_t)
+	if !okA || !okB {
+		return nil
+	}
+
+	if statA.Dev != statB.Dev {
+		return fmt.Errorf("hard links require %s and %s to be on the same filesystem", a, b)
+	}
+
+	return nil
+}
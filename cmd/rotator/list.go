@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// List prints all found backups with their size, age and tags without touching any file.
+// Backups which would be removed are tagged "remove" or "protected" if they are younger than MinAge.
+func (r *Rotator) List(asJSON bool) error {
+	r.selectFiles()
+	files := r.Export()
+
+	if asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(files)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSIZE\tAGE\tTAGS")
+	for _, backup := range files {
+		tags := strings.Join(backup.Tags, ",")
+		if !backup.Selected {
+			tags = "remove"
+			if time.Since(backup.Time) < r.MinAge {
+				tags = "protected"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", backup.Name, formatBytes(backup.SizeBytes), formatAge(time.Since(backup.Time)), tags)
+	}
+
+	return tw.Flush()
+}
+
+// listCommand prints the retention tags of all backups in the source directory.
+func listCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "Print all backups of the source directory with their retention tags without changing anything",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "source",
+				Usage:    "Source directory",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the backups as JSON",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			rotator := newRotator(c, c.String("source"), "")
+
+			if _, err := rotator.Read(); err != nil {
+				return err
+			}
+
+			return rotator.List(c.Bool("json"))
+		},
+	}
+}
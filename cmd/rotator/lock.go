@@ -0,0 +1,15 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrLocked is returned if another rotation holds the lock of the source directory.
+var ErrLocked = errors.New("source directory is locked by another rotation")
+
+// lockTimeout is the time AcquireLock waits for the lock to become free.
+const lockTimeout = 5 * time.Second
+
+// lockFileName is the name of the lock file created in the source directory.
+const lockFileName = ".rotator.lock"
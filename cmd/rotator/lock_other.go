@@ -0,0 +1,47 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Lock is an advisory pid file, flock is not available on this platform.
+// A stale pid file of a crashed process has to be removed manually.
+type Lock struct {
+	path string
+}
+
+// AcquireLock creates the pid file path.pid exclusively.
+// It returns ErrLocked if the file could not be created within 5 seconds.
+func AcquireLock(path string) (*Lock, error) {
+	path += ".pid"
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = file.WriteString(strconv.Itoa(os.Getpid()))
+			file.Close()
+			if err != nil {
+				os.Remove(path)
+				return nil, err
+			}
+			return &Lock{path: path}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrLocked
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// Release removes the pid file.
+func (l *Lock) Release() error {
+	return os.Remove(l.path)
+}
@@ -0,0 +1,48 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Lock is an exclusive flock on a lock file.
+type Lock struct {
+	file *os.File
+}
+
+// AcquireLock takes an exclusive lock on the file at path.
+// It returns ErrLocked if the lock could not be acquired within 5 seconds.
+func AcquireLock(path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return &Lock{file: file}, nil
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) {
+			file.Close()
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, ErrLocked
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// Release unlocks and closes the lock file. The file itself is kept,
+// removing it could let a waiting process lock a file which is already gone.
+func (l *Lock) Release() error {
+	defer l.file.Close()
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+}
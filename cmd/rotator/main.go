@@ -1,10 +1,19 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -12,9 +21,19 @@ import (
 
 // BackupFile represents a backup file
 type BackupFile struct {
-	Name string
-	Time time.Time
-	Tags []string
+	Name string    `json:"name"`
+	Time time.Time `json:"time"`
+	Tags []string  `json:"tags"`
+
+	// SizeBytes is the size of the file or the total size of the directory.
+	SizeBytes int64 `json:"sizeBytes"`
+
+	// Checksum is the hex encoded SHA-256 of the file, only set with ComputeChecksums.
+	Checksum string `json:"checksum,omitempty"`
+
+	// Selected and AgeHours are only populated by Export.
+	Selected bool    `json:"selected"`
+	AgeHours float64 `json:"ageHours"`
 }
 
 // Rotator represents the backup rotation implementation
@@ -22,26 +41,120 @@ type Rotator struct {
 	Dry bool
 
 	Keep       int
+	KeepHourly int
 	KeepDays   int
 	KeepWeeks  int
 	KeepMonths int
 	KeepYears  int
 
+	// RollingYear buckets the yearly backups by 365 day periods going back from now
+	// instead of calendar years.
+	RollingYear bool
+
+	// MinAge protects backups younger than this from being removed.
+	MinAge time.Duration
+
+	// MinFreeBytes and MinFreeInodes abort the rotation if the source or
+	// destination filesystem has less free space. Zero disables the check.
+	MinFreeBytes  int64
+	MinFreeInodes uint64
+
+	// ComputeChecksums makes Read compute the SHA-256 of every backup file.
+	ComputeChecksums bool
+
+	// MinSizeBytes ignores backups smaller than the given size, e.g. empty dumps of failed backups.
+	MinSizeBytes int64
+
 	SourceDir      string
 	DestinationDir string
 
+	// StateFile is the path of a JSON file tracking the rotation history across runs.
+	StateFile string
+
+	// EntryType is either "file" or "directory" for backups stored as timestamped directories.
+	// If empty, "directory" is used when all matching entries are directories.
+	EntryType string
+
+	// LinkMode is "symlink" (default), "hardlink" or "copy".
+	LinkMode string
+
+	// SortOrder is "newest" (default) or "oldest" and decides which backups
+	// are kept first, e.g. "oldest" keeps the first N backups as archival copies.
+	SortOrder string
+
+	// RelativeLinks creates symlinks relative to the destination directory,
+	// so they stay valid if the backup volume is mounted at another path.
+	RelativeLinks bool
+
+	// RestoreLink makes Restore create a symlink instead of a copy.
+	RestoreLink bool
+
+	// SingleLink creates only one link per backup using the tag with the highest priority.
+	SingleLink bool
+
+	// TagOrder is the tag priority used with SingleLink, highest first.
+	// If empty, DefaultTagOrder is used.
+	TagOrder []string
+
+	// TimeFormat is the layout of the captured timestamp as used by time.Parse.
+	// If empty, DefaultTimeFormat is used.
+	TimeFormat string
+
+	// Patterns are the regular expressions used to detect backup files.
+	// Each pattern must capture exactly one group: the timestamp.
+	Patterns []string
+
+	// Excludes are glob patterns of files to skip even if they match a pattern, e.g. *.inprogress.
+	Excludes []string
+
 	FoundFiles    []BackupFile
 	SelectedFiles []BackupFile
+	Protected     []BackupFile
+	Removed       []string
+
+	// Leftovers are files which should have been removed by the previous run but still exist.
+	Leftovers []string
+
+	// Destination stores the links instead of DestinationDir if set, e.g. a S3Destination.
+	Destination Destination
+
+	// PostHookCmd is run after each rotation with the stats as environment variables.
+	PostHookCmd []string
+
+	// HookTimeout is the maximum runtime of PostHookCmd. If zero, DefaultHookTimeout is used.
+	HookTimeout time.Duration
+
+	stats RotationStats
 }
 
 // clear removes all existing links from the destination directory.
+// Other destinations are not cleared, their links are deleted with the backups.
+// With LinkMode "copy" complete copies of the selected backups are kept, so they
+// are not copied again, see selectFiles. In dry run mode nothing is removed.
 func (r *Rotator) clear() error {
+	if r.Destination != nil || r.Dry {
+		return nil
+	}
+
 	files, err := os.ReadDir(r.DestinationDir)
 	if err != nil {
 		return err
 	}
 
+	keep := make(map[string]bool)
+	if r.LinkMode == "copy" {
+		for name, tags := range r.selectedTags() {
+			for _, tag := range r.linkTags(tags) {
+				keep[tag+"-"+name] = true
+			}
+		}
+	}
+
 	for _, file := range files {
+		if keep[file.Name()] && r.completeCopy(file) {
+			continue
+		}
+
 		err := os.Remove(r.DestinationDir + file.Name())
 		if err != nil {
 			return err
@@ -51,133 +164,670 @@ func (r *Rotator) clear() error {
 	return nil
 }
 
+// completeCopy reports whether the destination file is a complete copy of its backup.
+func (r *Rotator) completeCopy(file fs.DirEntry) bool {
+	_, name, _ := strings.Cut(file.Name(), "-")
+	src, err := os.Stat(r.SourceDir + name)
+	if err != nil {
+		return false
+	}
+	dst, err := file.Info()
+	if err != nil {
+		return false
+	}
+
+	return sameCopy(src, dst)
+}
+
+// DefaultPattern matches the timestamped sql dumps, e.g. 2024-01-15T03-00-00.sql.gz
+const DefaultPattern = `(\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2})\.sql\.gz`
+
+// DefaultTimeFormat is the layout of the timestamp captured by DefaultPattern.
+const DefaultTimeFormat = "2006-01-02T15-04-05"
+
+// timeFormat validates and returns the configured TimeFormat or the DefaultTimeFormat.
+// A format is valid if a reference time survives formatting and parsing with it.
+func (r *Rotator) timeFormat() (string, error) {
+	if r.TimeFormat == "" {
+		return DefaultTimeFormat, nil
+	}
+
+	reference := time.Date(2024, time.January, 15, 3, 0, 0, 0, time.UTC)
+	parsed, err := time.Parse(r.TimeFormat, reference.Format(r.TimeFormat))
+	if err != nil {
+		return "", fmt.Errorf("invalid time format %s: %w", r.TimeFormat, err)
+	}
+	if !parsed.Truncate(24 * time.Hour).Equal(reference.Truncate(24 * time.Hour)) {
+		return "", fmt.Errorf("invalid time format %s: year, month and day are required", r.TimeFormat)
+	}
+
+	return r.TimeFormat, nil
+}
+
+// patterns compiles the configured patterns or the DefaultPattern if none is configured.
+func (r *Rotator) patterns() ([]*regexp.Regexp, error) {
+	patterns := r.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{DefaultPattern}
+	}
+
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+		}
+		if re.NumSubexp() != 1 {
+			return nil, fmt.Errorf("pattern %s must capture exactly one group (the timestamp)", pattern)
+		}
+		res = append(res, re)
+	}
+
+	return res, nil
+}
+
 // Read reads the files in the source directory and populates the Files slice.
+// Files matching any of the patterns are merged into one slice.
 func (r *Rotator) Read() ([]BackupFile, error) {
+	res, err := r.patterns()
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := r.timeFormat()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pattern := range r.Excludes {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %s: %w", pattern, err)
+		}
+	}
+
+	if r.SortOrder != "" && r.SortOrder != "newest" && r.SortOrder != "oldest" {
+		return nil, fmt.Errorf("unknown sort order %s", r.SortOrder)
+	}
+
 	files, err := os.ReadDir(r.SourceDir)
 	if err != nil {
 		return nil, err
 	}
 
-	re := regexp.MustCompile(`(\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2})\.sql\.gz`)
-	r.FoundFiles = make([]BackupFile, 0)
+	type candidate struct {
+		backup BackupFile
+		isDir  bool
+	}
+
+	candidates := make([]candidate, 0)
+	dirs := 0
 	for _, file := range files {
-		matches := re.FindStringSubmatch(file.Name())
-		if len(matches) == 2 {
-			timestamp, err := time.Parse("2006-01-02T15-04-05", matches[1])
+		for _, re := range res {
+			matches := re.FindStringSubmatch(file.Name())
+			if len(matches) != 2 {
+				continue
+			}
+
+			if r.excluded(file.Name()) {
+				slog.Debug("skipping excluded file", "file", r.SourceDir+file.Name())
+				if r.Dry {
+					fmt.Println("DryRun: exclude", r.SourceDir+file.Name())
+				}
+				break
+			}
+
+			timestamp, err := time.Parse(layout, matches[1])
 			if err != nil {
 				fmt.Println("error parsing timestamp:", err)
-				continue
+				break
+			}
+
+			info, err := file.Info()
+			if err != nil {
+				fmt.Println("error reading file info:", err)
+				break
+			}
+			size := info.Size()
+			if file.IsDir() {
+				size = dirSize(r.SourceDir + file.Name())
 			}
-			r.FoundFiles = append(r.FoundFiles, BackupFile{
-				Name: file.Name(),
-				Time: timestamp,
+			if size < r.MinSizeBytes {
+				break
+			}
+
+			candidates = append(candidates, candidate{
+				backup: BackupFile{
+					Name:      file.Name(),
+					Time:      timestamp,
+					SizeBytes: size,
+				},
+				isDir: file.IsDir(),
 			})
+			if file.IsDir() {
+				dirs++
+			}
+			break
+		}
+	}
+
+	// detect the entry type if it is not configured
+	if r.EntryType == "" {
+		r.EntryType = "file"
+		if dirs > 0 && dirs == len(candidates) {
+			r.EntryType = "directory"
+		}
+	}
+	if r.EntryType != "file" && r.EntryType != "directory" {
+		return nil, fmt.Errorf("unknown entry type %s", r.EntryType)
+	}
+
+	r.FoundFiles = make([]BackupFile, 0)
+	for _, c := range candidates {
+		if c.isDir != (r.EntryType == "directory") {
+			continue
+		}
+
+		// checksums of backup directories are not supported
+		if r.ComputeChecksums && !c.isDir {
+			if c.backup.Checksum, err = fileChecksum(r.SourceDir + c.backup.Name); err != nil {
+				return nil, fmt.Errorf("error computing checksum of %s: %w", c.backup.Name, err)
+			}
 		}
+		r.FoundFiles = append(r.FoundFiles, c.backup)
 	}
 
-	// Sort backups by time (newest first)
+	// Sort backups by time (newest first unless SortOrder is "oldest")
 	sort.Slice(r.FoundFiles, func(i, j int) bool {
+		if r.SortOrder == "oldest" {
+			return r.FoundFiles[i].Time.Before(r.FoundFiles[j].Time)
+		}
 		return r.FoundFiles[i].Time.After(r.FoundFiles[j].Time)
 	})
 
 	return r.FoundFiles, nil
 }
 
-// link creates symlinks in the destination directory prepending the "biggest" tag.
-// The tag order is: keep, daily, weekly, monthly, yearly where yearly is the "biggest".
+// excluded reports whether name matches one of the Excludes glob patterns.
+func (r *Rotator) excluded(name string) bool {
+	for _, pattern := range r.Excludes {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// relativeSymlink creates a symlink at destPath pointing to srcPath relative to the link's directory.
+func relativeSymlink(srcPath, destPath string) error {
+	absSrc, err := filepath.Abs(srcPath)
+	if err != nil {
+		return err
+	}
+	absDest, err := filepath.Abs(destPath)
+	if err != nil {
+		return err
+	}
+
+	target, err := filepath.Rel(filepath.Dir(absDest), absSrc)
+	if err != nil {
+		return err
+	}
+
+	return os.Symlink(target, destPath)
+}
+
+// DefaultTagOrder is the tag priority used for single links, highest first.
+var DefaultTagOrder = []string{"yearly", "monthly", "weekly", "daily", "hourly", "keep"}
+
+// tagPriority returns the configured TagOrder or DefaultTagOrder.
+func (r *Rotator) tagPriority() []string {
+	if len(r.TagOrder) == 0 {
+		return DefaultTagOrder
+	}
+
+	return r.TagOrder
+}
+
+// linkTags returns the tags a backup is linked with. Without SingleLink these are all tags,
+// otherwise only the tag with the highest priority in TagOrder.
+func (r *Rotator) linkTags(tags []string) []string {
+	if !r.SingleLink || len(tags) == 0 {
+		return tags
+	}
+
+	for _, tag := range r.tagPriority() {
+		if slices.Contains(tags, tag) {
+			return []string{tag}
+		}
+	}
+
+	return tags[:1]
+}
+
+// link creates symlinks in the destination directory prepending the tags.
+// With SingleLink only the "biggest" tag is used, see DefaultTagOrder.
+// If LinkMode is "hardlink" hard links are created instead of symlinks,
+// "copy" stores real files which are copied if the destination is on another filesystem.
+// If a Destination is set, the backups are stored there instead.
 func (r *Rotator) link() error {
+	dest, err := r.destination()
+	if err != nil {
+		return err
+	}
+	if r.Destination != nil && r.EntryType == "directory" {
+		return fmt.Errorf("backup directories can only be linked locally")
+	}
+
+	tags := r.selectedTags()
+	linked := make(map[string]bool)
 	for _, result := range r.SelectedFiles {
-		for _, tag := range result.Tags {
-			srcPath := r.SourceDir + result.Name
-			destPath := r.DestinationDir + tag + "-" + result.Name
-			if _, err := os.Lstat(destPath); os.IsNotExist(err) {
-				err := os.Symlink(srcPath, destPath)
-				if err != nil {
-					return err
-				}
+		// backups with multiple tags are selected multiple times
+		if linked[result.Name] {
+			continue
+		}
+		linked[result.Name] = true
+
+		for _, tag := range r.linkTags(tags[result.Name]) {
+			if err := r.put(dest, tag+"-"+result.Name, r.SourceDir+result.Name); err != nil {
+				return err
 			}
+			r.stats.Linked++
 		}
 	}
 
+	if r.Destination != nil {
+		return r.removeStaleLinks(dest)
+	}
+
 	return nil
 }
 
-func (r *Rotator) remove() error {
+// put stores the backup at srcPath as name in dest.
+func (r *Rotator) put(dest Destination, name, srcPath string) error {
+	f, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return dest.Put(name, f)
+}
+
+// selectedTags returns the unique tags of every selected file by name.
+// A backup is selected once per tag and a tag may be listed more than once,
+// linking a duplicate tag would fail because the link already exists.
+func (r *Rotator) selectedTags() map[string][]string {
+	tags := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, backup := range r.SelectedFiles {
+		for _, tag := range backup.Tags {
+			if key := tag + "-" + backup.Name; !seen[key] {
+				seen[key] = true
+				tags[backup.Name] = append(tags[backup.Name], tag)
+			}
+		}
+	}
+
+	return tags
+}
+
+// unselected returns the backups which are not selected, split into the ones
+// to remove and the ones protected by MinAge.
+func (r *Rotator) unselected() (remove []BackupFile, protected []BackupFile) {
 	resultMap := make(map[string]bool)
 	for _, result := range r.SelectedFiles {
 		resultMap[result.Name] = true
 	}
+
+	remove = make([]BackupFile, 0)
+	protected = make([]BackupFile, 0)
 	for _, backup := range r.FoundFiles {
-		if !resultMap[backup.Name] {
-			if !r.Dry {
-				if err := os.Remove(r.SourceDir + backup.Name); err != nil {
+		if resultMap[backup.Name] {
+			continue
+		}
+		if time.Since(backup.Time) < r.MinAge {
+			protected = append(protected, backup)
+			continue
+		}
+		remove = append(remove, backup)
+	}
+
+	return remove, protected
+}
+
+// dirSize returns the total size of the regular files in the directory.
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+
+	return size
+}
+
+func (r *Rotator) remove() error {
+	var remove []BackupFile
+	remove, r.Protected = r.unselected()
+	r.Removed = make([]string, 0)
+	for _, backup := range remove {
+		if !r.Dry {
+			if err := os.RemoveAll(r.SourceDir + backup.Name); err != nil {
+				return err
+			}
+		} else {
+			fmt.Println("DryRun: remove", r.SourceDir+backup.Name)
+		}
+
+		// links of local destinations are already cleared
+		if r.Destination != nil {
+			for _, tag := range r.tagPriority() {
+				if err := r.Destination.Delete(tag + "-" + backup.Name); err != nil {
 					return err
 				}
-			} else {
-				fmt.Println("DryRun: remove", r.SourceDir+backup.Name)
 			}
 		}
+
+		r.Removed = append(r.Removed, backup.Name)
+		if r.Dry {
+			r.stats.DryRemoved++
+			r.stats.DryFreedBytes += backup.SizeBytes
+		} else {
+			r.stats.Removed++
+			r.stats.FreedBytes += backup.SizeBytes
+		}
 	}
+
+	if r.Dry && len(r.Protected) > 0 {
+		fmt.Printf("DryRun: WARNING %d backups are younger than %s and will not be removed:\n", len(r.Protected), r.MinAge)
+		for _, backup := range r.Protected {
+			fmt.Println("DryRun: protected", r.SourceDir+backup.Name)
+		}
+	}
+
 	return nil
 }
 
-// Rotate implements the rotation strategy.
-func (r *Rotator) Rotate() {
-	r.clear()
+// selectFiles selects the backups to keep and assigns their tags.
+// It has no side effects on the filesystem.
+func (r *Rotator) selectFiles() {
+	// start from scratch so the selection can be computed repeatedly
+	for i := range r.FoundFiles {
+		r.FoundFiles[i].Tags = nil
+	}
 
-	// keep the first n backups
-	r.SelectedFiles = r.FoundFiles[:r.Keep]
-	for i := 0; i < r.Keep && i < len(r.FoundFiles); i++ {
+	// keep the first n backups, copied so appending does not overwrite FoundFiles
+	keep := min(r.Keep, len(r.FoundFiles))
+	r.SelectedFiles = append([]BackupFile(nil), r.FoundFiles[:keep]...)
+	for i := 0; i < keep; i++ {
 		r.FoundFiles[i].Tags = append(r.FoundFiles[i].Tags, "keep")
+		r.SelectedFiles[i].Tags = r.FoundFiles[i].Tags
 	}
 
-	// Collect backups (up to Keep[Days, Weeks, Months, Years]) beginning from the newest
+	// Collect backups (up to Keep[Hourly, Days, Weeks, Months, Years]) in SortOrder, beginning from the newest by default
+	hourly := make(map[string]BackupFile)
 	daily := make(map[string]BackupFile)
 	weekly := make(map[string]BackupFile)
 	monthly := make(map[string]BackupFile)
 	yearly := make(map[string]BackupFile)
 
-	for _, backup := range r.FoundFiles[r.Keep:] {
+	for i := keep; i < len(r.FoundFiles); i++ {
+		// tag the element, not a copy, so FoundFiles reflects the selection
+		backup := &r.FoundFiles[i]
+		hour := backup.Time.Format("2006-01-02T15")
 		date := backup.Time.Format("2006-01-02")
 		_, weekNumber := backup.Time.ISOWeek()
 		week := fmt.Sprintf("%d-W%02d", backup.Time.Year(), weekNumber)
 		month := backup.Time.Format("2006-01")
 		year := backup.Time.Format("2006")
+		if r.RollingYear {
+			// 0 is the last 365 days, 1 the 365 days before and so on
+			year = strconv.Itoa(int(time.Since(backup.Time).Hours() / 8760))
+		}
+
+		if _, exists := hourly[hour]; !exists && len(hourly) < r.KeepHourly {
+			backup.Tags = append(backup.Tags, "hourly")
+			hourly[hour] = *backup
+			r.SelectedFiles = append(r.SelectedFiles, *backup)
+		}
 
 		if _, exists := daily[date]; !exists && len(daily) < r.KeepDays {
 			backup.Tags = append(backup.Tags, "daily")
-			daily[date] = backup
-			r.SelectedFiles = append(r.SelectedFiles, backup)
+			daily[date] = *backup
+			r.SelectedFiles = append(r.SelectedFiles, *backup)
 		}
 
 		if _, exists := weekly[week]; !exists && len(weekly) < r.KeepWeeks {
 			backup.Tags = append(backup.Tags, "weekly")
-			weekly[week] = backup
-			r.SelectedFiles = append(r.SelectedFiles, backup)
+			weekly[week] = *backup
+			r.SelectedFiles = append(r.SelectedFiles, *backup)
 		}
 
 		if _, exists := monthly[month]; !exists && len(monthly) < r.KeepMonths {
 			backup.Tags = append(backup.Tags, "monthly")
-			monthly[month] = backup
-			r.SelectedFiles = append(r.SelectedFiles, backup)
+			monthly[month] = *backup
+			r.SelectedFiles = append(r.SelectedFiles, *backup)
 		}
 
 		if _, exists := yearly[year]; !exists && len(yearly) < r.KeepYears {
 			backup.Tags = append(backup.Tags, "yearly")
-			yearly[year] = backup
-			r.SelectedFiles = append(r.SelectedFiles, backup)
+			yearly[year] = *backup
+			r.SelectedFiles = append(r.SelectedFiles, *backup)
+		}
+	}
+}
+
+// Rotate implements the rotation strategy.
+// It stops before linking or removing any file if the free space is too low
+// or the destination directory can not be cleared. Errors while linking or
+// removing are returned after the rotation so the post hook is not run.
+func (r *Rotator) Rotate() error {
+	start := time.Now()
+	r.stats = RotationStats{Total: len(r.FoundFiles)}
+	defer func() {
+		r.stats.Duration = time.Since(start)
+	}()
+
+	for _, dir := range []string{r.SourceDir, r.DestinationDir} {
+		if err := r.checkFreeSpace(dir); err != nil {
+			return fmt.Errorf("error checking free space: %w", err)
+		}
+	}
+
+	if r.StateFile != "" {
+		if err := r.checkState(); err != nil {
+			fmt.Printf("error reading state file: %v\n", err)
 		}
 	}
 
-	// Create Symlinks for the kept backups
+	// the selection has no side effects, clear keeps the copies of the selected backups
+	r.selectFiles()
+
+	if err := r.clear(); err != nil {
+		return fmt.Errorf("error clearing destination: %w", err)
+	}
+
+	// Create Symlinks for the kept backups, the removal still runs if linking failed
+	var errs []error
 	if err := r.link(); err != nil {
-		fmt.Printf("error linking files: %v\n", err)
+		errs = append(errs, fmt.Errorf("error linking files: %w", err))
 	}
 
 	// Remove backups that are not selected
 	if err := r.remove(); err != nil {
-		fmt.Printf("error removing files: %v\n", err)
+		errs = append(errs, fmt.Errorf("error removing files: %w", err))
+	}
+
+	r.stats.Kept = r.stats.Total - r.stats.Removed - r.stats.DryRemoved
+
+	if r.StateFile != "" && !r.Dry {
+		if err := r.writeState(); err != nil {
+			fmt.Printf("error writing state file: %v\n", err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// withSlash makes sure the directory ends with a slash.
+func withSlash(dir string) string {
+	if dir == "" || dir[len(dir)-1] != '/' {
+		dir += "/"
 	}
+
+	return dir
+}
+
+// tagOrder splits the comma separated tag priority.
+func tagOrder(value string) []string {
+	var order []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			order = append(order, tag)
+		}
+	}
+
+	return order
+}
+
+// newRotator creates a rotator for the given directories configured by the CLI flags.
+func newRotator(c *cli.Context, srcDir, dstDir string) *Rotator {
+	return &Rotator{
+		Dry:              c.Bool("dry"),
+		Keep:             c.Int("keep"),
+		KeepHourly:       c.Int("keep-hourly"),
+		KeepDays:         c.Int("keep-days"),
+		KeepWeeks:        c.Int("keep-weeks"),
+		KeepMonths:       c.Int("keep-months"),
+		KeepYears:        c.Int("keep-years"),
+		RollingYear:      c.Bool("rolling-year"),
+		MinAge:           time.Duration(c.Int("min-age-hours")) * time.Hour,
+		MinFreeBytes:     c.Int64("min-free-bytes"),
+		MinFreeInodes:    c.Uint64("min-free-inodes"),
+		MinSizeBytes:     c.Int64("min-size-bytes"),
+		ComputeChecksums: c.Bool("checksums") || c.String("checksum-file") != "",
+		SourceDir:        withSlash(srcDir),
+		DestinationDir:   withSlash(dstDir),
+		Patterns:         c.StringSlice("pattern"),
+		Excludes:         c.StringSlice("exclude"),
+		LinkMode:         c.String("link-mode"),
+		TimeFormat:       c.String("time-format"),
+		RelativeLinks:    c.Bool("relative-links"),
+		SingleLink:       c.Bool("single-link"),
+		TagOrder:         tagOrder(c.String("tag-order")),
+		SortOrder:        c.String("sort-order"),
+		StateFile:        c.String("state-file"),
+		EntryType:        c.String("entry-type"),
+		PostHookCmd:      shellCommand(c.String("post-hook")),
+		HookTimeout:      hookTimeout(c),
+	}
+}
+
+// rotate runs the rotation and the post rotation steps requested by the CLI flags.
+func rotate(c *cli.Context, rotator *Rotator) (err error) {
+	if url := c.String("notify-url"); url != "" {
+		defer func() {
+			notify(url, rotator, err)
+		}()
+	}
+
+	if rotator.Destination == nil {
+		dest, err := remoteDestination(c, rotator.Dry)
+		if err != nil {
+			return err
+		}
+		if dest != nil {
+			if c.Bool("verify") {
+				return fmt.Errorf("--verify is only supported for local destinations")
+			}
+
+			// connections are only kept for a single rotation
+			rotator.Destination = dest
+			defer func() {
+				if closer, ok := dest.(io.Closer); ok {
+					closer.Close()
+				}
+				rotator.Destination = nil
+			}()
+		}
+	}
+
+	lock, err := AcquireLock(rotator.SourceDir + lockFileName)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	files, err := rotator.Read()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return err
+	}
+
+	if rotator.Dry {
+		fmt.Print(rotator.DryRunReport())
+	}
+
+	if err := rotator.Rotate(); err != nil {
+		return err
+	}
+
+	if !c.Bool("skip-validate") {
+		if err := rotator.ValidateLinks(); err != nil {
+			return err
+		}
+	}
+
+	if c.Bool("json") {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(rotator.Stats()); err != nil {
+			return err
+		}
+	} else {
+		for _, file := range rotator.SelectedFiles {
+			fmt.Println("Linked file:", file.Name, "Tags:", file.Tags)
+		}
+		if rotator.Dry {
+			fmt.Println("DryRun: would free space:", formatBytes(rotator.Stats().DryFreedBytes))
+		} else {
+			fmt.Println("Freed space:", formatBytes(rotator.Stats().FreedBytes))
+		}
+	}
+
+	if path := c.String("export-json"); path != "" {
+		if err := writeExport(path, rotator.Export()); err != nil {
+			return err
+		}
+	}
+
+	if path := c.String("checksum-file"); path != "" {
+		if err := rotator.WriteChecksumFile(path); err != nil {
+			return err
+		}
+	}
+
+	if c.Bool("verify") {
+		errs := rotator.Verify()
+		for _, err := range errs {
+			fmt.Println("Broken link:", err.Error())
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("%d broken links found", len(errs))
+		}
+	}
+
+	rotator.runPostHook()
+
+	return nil
 }
 
 func main() {
@@ -192,6 +842,10 @@ func main() {
 				Usage: "Number of backups to keep",
 				Value: 5,
 			},
+			&cli.IntFlag{
+				Name:  "keep-hourly",
+				Usage: "Number of hourly backups to keep",
+			},
 			&cli.IntFlag{
 				Name:  "keep-days",
 				Usage: "Number of daily backups to keep",
@@ -212,11 +866,36 @@ func main() {
 				Usage: "Number of yearly backups to keep",
 				Value: 2,
 			},
+			&cli.BoolFlag{
+				Name:  "rolling-year",
+				Usage: "Keep yearly backups per 365 days going back from now instead of per calendar year",
+			},
+			&cli.IntFlag{
+				Name:  "min-age-hours",
+				Usage: "Never remove backups younger than this number of hours",
+				Value: 1,
+			},
+			&cli.Int64Flag{
+				Name:  "min-free-bytes",
+				Usage: "Abort if source or destination have less free bytes (0 disables the check)",
+			},
+			&cli.Uint64Flag{
+				Name:  "min-free-inodes",
+				Usage: "Abort if source or destination have less free inodes (0 disables the check)",
+			},
+			&cli.Int64Flag{
+				Name:  "min-size-bytes",
+				Usage: "Ignore backups smaller than the given size in bytes, e.g. empty dumps",
+			},
 			&cli.BoolFlag{
 				Name:  "dry",
 				Usage: "Dry run",
 				Count: &dryCount,
 			},
+			&cli.BoolFlag{
+				Name:  "debug",
+				Usage: "Log debug messages, e.g. the excluded files",
+			},
 			&cli.StringFlag{
 				Name:  "source",
 				Usage: "Source directory",
@@ -225,53 +904,158 @@ func main() {
 				Name:  "destination",
 				Usage: "Destination directory",
 			},
+			&cli.BoolFlag{
+				Name:  "verify",
+				Usage: "Verify the created symlinks after rotation",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-validate",
+				Usage: "Do not check that a link was created for every tag of the selected backups",
+			},
+			&cli.BoolFlag{
+				Name:  "checksums",
+				Usage: "Compute the SHA-256 of every backup, verified by --verify",
+			},
+			&cli.StringFlag{
+				Name:  "checksum-file",
+				Usage: "Write the SHA-256 of all retained backups to this file in sha256sum format",
+			},
+			&cli.StringSliceFlag{
+				Name:  "pattern",
+				Usage: "Regex with one capture group for the timestamp, can be repeated",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude",
+				Usage: "Glob pattern of files to skip, e.g. *.inprogress, can be repeated",
+			},
+			&cli.StringFlag{
+				Name:  "time-format",
+				Usage: "Go time layout of the captured timestamp",
+				Value: DefaultTimeFormat,
+			},
+			&cli.StringFlag{
+				Name:  "entry-type",
+				Usage: "Type of the backups: file or directory (default: detected)",
+			},
+			&cli.StringFlag{
+				Name:  "link-mode",
+				Usage: "Link type to create: symlink, hardlink or copy",
+				Value: "symlink",
+			},
+			&cli.StringFlag{
+				Name:  "state-file",
+				Usage: "JSON file to track the rotation history across runs",
+			},
+			&cli.BoolFlag{
+				Name:  "relative-links",
+				Usage: "Create relative instead of absolute symlinks",
+			},
+			&cli.BoolFlag{
+				Name:  "single-link",
+				Usage: "Create only one link per backup using the tag with the highest priority",
+			},
+			&cli.StringFlag{
+				Name:  "tag-order",
+				Usage: "Comma separated tag priority for --single-link, highest first",
+				Value: strings.Join(DefaultTagOrder, ","),
+			},
+			&cli.StringFlag{
+				Name:  "sort-order",
+				Usage: "Order in which backups are kept: newest or oldest",
+				Value: "newest",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the rotation stats as JSON",
+			},
+			&cli.BoolFlag{
+				Name:  "watch",
+				Usage: "Keep running and rotate whenever a new backup appears in the source directory",
+			},
+			&cli.StringFlag{
+				Name:  "notify-url",
+				Usage: "Webhook URL receiving a JSON POST with the result of every rotation",
+			},
+			&cli.StringFlag{
+				Name:  "post-hook",
+				Usage: "Command to run after each rotation, e.g. \"rsync -a /backups/ offsite:/backups/\"",
+			},
+			&cli.DurationFlag{
+				Name:  "hook-timeout",
+				Usage: "Maximum runtime of the post hook, overrides wls.rotator.hookTimeout (default: 60s)",
+			},
+			&cli.StringFlag{
+				Name:  "export-json",
+				Usage: "Write the state of all found backups as JSON to this file after rotation",
+			},
+			&cli.StringFlag{
+				Name:  "s3-bucket",
+				Usage: "Upload the selected backups to this S3 bucket instead of linking them",
+			},
+			&cli.StringFlag{
+				Name:  "s3-prefix",
+				Usage: "Key prefix of the uploaded backups",
+			},
+			&cli.StringFlag{
+				Name:  "s3-region",
+				Usage: "Region of the S3 bucket (default: AWS_REGION)",
+			},
+			&cli.StringFlag{
+				Name:  "sftp-host",
+				Usage: "Upload the selected backups to this SFTP server (host[:port]) instead of linking them",
+			},
+			&cli.StringFlag{
+				Name:  "sftp-user",
+				Usage: "User of the SFTP server",
+			},
+			&cli.StringFlag{
+				Name:  "sftp-key",
+				Usage: "Path of the private key used to authenticate at the SFTP server",
+			},
+			&cli.StringFlag{
+				Name:  "sftp-path",
+				Usage: "Directory on the SFTP server",
+			},
+			&cli.StringFlag{
+				Name:  "config",
+				Usage: "YAML file with a list of databases to rotate and the wls.rotator settings",
+			},
 		},
-		Action: func(c *cli.Context) error {
-			srcDir := c.String("source")
-			if srcDir[len(srcDir)-1] != '/' {
-				srcDir += "/"
-			}
-
-			dstDir := c.String("destination")
-			if dstDir[len(dstDir)-1] != '/' {
-				dstDir += "/"
+		Before: func(c *cli.Context) error {
+			if c.Bool("debug") {
+				slog.SetLogLoggerLevel(slog.LevelDebug)
 			}
-
+			return nil
+		},
+		Commands: []*cli.Command{
+			restoreCommand(),
+			listCommand(),
+			pruneCommand(),
+		},
+		Action: func(c *cli.Context) error {
 			if dryCount > 0 {
 				fmt.Println("Dry run enabled")
 			}
 
-			rotator := Rotator{
-				Dry:            (dryCount > 0),
-				Keep:           c.Int("keep"),
-				KeepDays:       c.Int("keep-days"),
-				KeepWeeks:      c.Int("keep-weeks"),
-				KeepMonths:     c.Int("keep-months"),
-				KeepYears:      c.Int("keep-years"),
-				SourceDir:      srcDir,
-				DestinationDir: dstDir,
-			}
-
-			files, err := rotator.Read()
-			if err != nil {
-				return err
-			}
-			if len(files) == 0 {
+			if err := setupConfig(c.String("config")); err != nil {
 				return err
 			}
 
-			rotator.Rotate()
+			if c.String("config") != "" {
+				return rotateConfig(c, c.String("config"))
+			}
 
-			for _, file := range rotator.SelectedFiles {
-				fmt.Println("Linked file:", file.Name, "Tags:", file.Tags)
+			rotator := newRotator(c, c.String("source"), c.String("destination"))
+			if c.Bool("watch") {
+				return watch(c, rotator)
 			}
 
-			return nil
+			return rotate(c, rotator)
 		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
-		fmt.Fprintf(os.Stderr, err.Error()+"\n")
+		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newTestRotator returns a rotator for a temporary source directory containing the given
+// backups and an empty temporary destination directory.
+func newTestRotator(t *testing.T, names ...string) *Rotator {
+	t.Helper()
+
+	src, dst := t.TempDir(), t.TempDir()
+	for _, name := range names {
+		writeBackup(t, src, name)
+	}
+
+	return &Rotator{
+		SourceDir:      withSlash(src),
+		DestinationDir: withSlash(dst),
+	}
+}
+
+// writeBackup creates the backup name in dir.
+func writeBackup(t *testing.T, dir, name string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// names returns the names of the backups.
+func names(backups []BackupFile) []string {
+	result := make([]string, len(backups))
+	for i, backup := range backups {
+		result[i] = backup.Name
+	}
+
+	return result
+}
+
+func TestSelectedTagsDeduplicates(t *testing.T) {
+	const name = "2024-01-17T03-00-00.sql.gz"
+	r := newTestRotator(t, name)
+	dest := &memDestination{objects: map[string][]byte{}}
+	r.Destination = dest
+
+	// a backup selected by several rules is part of the selection multiple times with the same tags
+	backup := BackupFile{Name: name, Tags: []string{"daily", "weekly", "daily"}}
+	r.SelectedFiles = []BackupFile{backup, backup}
+
+	if got, want := r.selectedTags()[name], []string{"daily", "weekly"}; !slices.Equal(got, want) {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+
+	if err := r.link(); err != nil {
+		t.Fatal(err)
+	}
+	if r.stats.Linked != 2 {
+		t.Errorf("linked %d times, want 2", r.stats.Linked)
+	}
+	if got, _ := dest.List(); len(got) != 2 {
+		t.Errorf("links = %v, want one per tag", got)
+	}
+}
+
+func TestSortOrder(t *testing.T) {
+	backups := []string{
+		"2024-01-13T03-00-00.sql.gz",
+		"2024-01-14T03-00-00.sql.gz",
+		"2024-01-15T03-00-00.sql.gz",
+		"2024-01-16T03-00-00.sql.gz",
+	}
+
+	tests := []struct {
+		order string
+		found []string
+		kept  []string
+	}{
+		{
+			order: "newest",
+			found: []string{backups[3], backups[2], backups[1], backups[0]},
+			kept:  []string{backups[3], backups[2]},
+		},
+		{
+			order: "oldest",
+			found: []string{backups[0], backups[1], backups[2], backups[3]},
+			kept:  []string{backups[0], backups[1]},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			r := newTestRotator(t, backups...)
+			r.SortOrder = tt.order
+			r.KeepDays = 2
+
+			found, err := r.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := names(found); !slices.Equal(got, tt.found) {
+				t.Errorf("found = %v, want %v", got, tt.found)
+			}
+
+			r.selectFiles()
+			if got := names(r.SelectedFiles); !slices.Equal(got, tt.kept) {
+				t.Errorf("kept = %v, want %v", got, tt.kept)
+			}
+		})
+	}
+}
+
+func TestRelativeLinks(t *testing.T) {
+	const name = "2024-01-17T03-00-00.sql.gz"
+	volume := t.TempDir()
+	for _, dir := range []string{"backups", "links"} {
+		if err := os.Mkdir(filepath.Join(volume, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeBackup(t, filepath.Join(volume, "backups"), name)
+
+	r := &Rotator{
+		SourceDir:      withSlash(filepath.Join(volume, "backups")),
+		DestinationDir: withSlash(filepath.Join(volume, "links")),
+		KeepDays:       1,
+		RelativeLinks:  true,
+	}
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := os.Readlink(filepath.Join(volume, "links", "daily-"+name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.IsAbs(target) {
+		t.Errorf("link target %s is absolute", target)
+	}
+
+	// the link resolves after the volume is mounted at another path
+	moved := filepath.Join(t.TempDir(), "mnt")
+	if err := os.Rename(volume, moved); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(moved, "links", "daily-"+name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != name {
+		t.Errorf("link resolves to %q, want the backup %q", content, name)
+	}
+}
+
+func TestVerifySingleLink(t *testing.T) {
+	const name = "2024-01-17T03-00-00.sql.gz"
+	r := newTestRotator(t, name)
+	r.SingleLink = true
+
+	backup := BackupFile{Name: name, Tags: []string{"daily", "weekly"}}
+	r.SelectedFiles = []BackupFile{backup, backup}
+	if err := r.link(); err != nil {
+		t.Fatal(err)
+	}
+
+	if errs := r.Verify(); len(errs) != 0 {
+		t.Errorf("verify errors = %v, want none for the single link", errs)
+	}
+
+	if err := os.Remove(r.DestinationDir + "weekly-" + name); err != nil {
+		t.Fatal(err)
+	}
+	errs := r.Verify()
+	if len(errs) != 1 || errs[0].Tag != "weekly" {
+		t.Errorf("verify errors = %v, want one for the removed weekly link", errs)
+	}
+}
+
+func TestExcludeInProgress(t *testing.T) {
+	const inProgress = "2024-01-13T03-00-00.sql.gz.inprogress"
+	r := newTestRotator(t,
+		"2024-01-16T03-00-00.sql.gz",
+		"2024-01-17T03-00-00.sql.gz",
+		inProgress,
+	)
+	r.Excludes = []string{"*.inprogress"}
+	r.KeepDays = 1
+
+	found, err := r.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(names(found), inProgress) {
+		t.Fatalf("found = %v, want the in-progress backup excluded", names(found))
+	}
+
+	if err := r.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(r.SourceDir + inProgress); err != nil {
+		t.Errorf("in-progress backup was removed: %v", err)
+	}
+	if _, err := os.Stat(r.SourceDir + "2024-01-16T03-00-00.sql.gz"); !os.IsNotExist(err) {
+		t.Errorf("old backup was not removed: %v", err)
+	}
+}
+
+func TestExcludeInvalidPattern(t *testing.T) {
+	r := newTestRotator(t, "2024-01-17T03-00-00.sql.gz")
+	r.Excludes = []string{"[.inprogress"}
+
+	if _, err := r.Read(); err == nil {
+		t.Error("Read accepted an invalid exclude pattern")
+	}
+}
+
+func TestRollingYear(t *testing.T) {
+	// 18 monthly backups, the first 13 are younger than 365 days
+	now := time.Now().UTC()
+	backups := make([]string, 18)
+	for k := range backups {
+		backups[k] = now.AddDate(0, 0, -30*k).Format(DefaultTimeFormat) + ".sql.gz"
+	}
+
+	// calendarYears returns the newest backup of the first n calendar years
+	calendarYears := func(n int) []string {
+		kept := make([]string, 0, n)
+		seen := make(map[int]bool)
+		for k := range backups {
+			year := now.AddDate(0, 0, -30*k).Year()
+			if !seen[year] && len(seen) < n {
+				seen[year] = true
+				kept = append(kept, backups[k])
+			}
+		}
+		return kept
+	}
+
+	tests := []struct {
+		name    string
+		rolling bool
+		years   int
+		kept    []string
+	}{
+		{"rolling one year", true, 1, []string{backups[0]}},
+		{"rolling two years", true, 2, []string{backups[0], backups[13]}},
+		{"rolling more years than backups", true, 3, []string{backups[0], backups[13]}},
+		{"calendar two years", false, 2, calendarYears(2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRotator(t, backups...)
+			r.RollingYear = tt.rolling
+			r.KeepYears = tt.years
+
+			if _, err := r.Read(); err != nil {
+				t.Fatal(err)
+			}
+			r.selectFiles()
+
+			if got := names(r.SelectedFiles); !slices.Equal(got, tt.kept) {
+				t.Errorf("kept = %v, want %v", got, tt.kept)
+			}
+		})
+	}
+}
+
+func TestSelectFilesTagsFoundFiles(t *testing.T) {
+	r := newTestRotator(t,
+		"2024-01-15T03-00-00.sql.gz",
+		"2024-01-16T03-00-00.sql.gz",
+		"2024-01-17T03-00-00.sql.gz",
+	)
+	r.Keep = 1
+	r.KeepDays = 2
+
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+	r.selectFiles()
+
+	// FoundFiles reflects the selection, not only SelectedFiles
+	for i, want := range [][]string{{"keep"}, {"daily"}, {"daily"}} {
+		if got := r.FoundFiles[i].Tags; !slices.Equal(got, want) {
+			t.Errorf("FoundFiles[%d].Tags = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestKeepHourly(t *testing.T) {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	backups := make([]string, 48)
+	for i := range backups {
+		backups[i] = start.Add(time.Duration(i)*time.Hour).Format(DefaultTimeFormat) + ".sql.gz"
+	}
+
+	r := newTestRotator(t, backups...)
+	r.KeepHourly = 24
+	r.KeepDays = 2
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tags := r.selectedTags()
+	for i, name := range backups {
+		var want []string
+		if i >= 24 {
+			want = append(want, "hourly")
+		}
+		// the last backup of each day is the daily one
+		if i == 23 || i == 47 {
+			want = append(want, "daily")
+		}
+		if got := tags[name]; !slices.Equal(got, want) {
+			t.Errorf("%s: tags = %v, want %v", name, got, want)
+		}
+
+		_, err := os.Stat(r.SourceDir + name)
+		if kept := len(want) > 0; kept != (err == nil) {
+			t.Errorf("%s: kept = %t, want %t", name, err == nil, kept)
+		}
+	}
+
+	if got := r.Stats().Removed; got != 23 {
+		t.Errorf("removed %d backups, want 23", got)
+	}
+	links, err := filepath.Glob(filepath.Join(r.DestinationDir, "hourly-*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 24 {
+		t.Errorf("%d hourly links, want 24", len(links))
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// notifyTimeout is the maximum duration of the webhook call.
+const notifyTimeout = 10 * time.Second
+
+// Notification is the JSON body posted to the notify URL after a successful rotation.
+type Notification struct {
+	Status     string `json:"status"`
+	Removed    int    `json:"removed"`
+	Retained   int    `json:"retained"`
+	FreedBytes int64  `json:"freedBytes"`
+}
+
+// ErrorNotification is the JSON body posted to the notify URL after a failed rotation.
+type ErrorNotification struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// notify posts the result of the rotation to url.
+// Failures are only printed as warning so monitoring outages do not affect the rotation.
+func notify(url string, rotator *Rotator, rotateErr error) {
+	stats := rotator.Stats()
+	var n any = Notification{
+		Status:     "ok",
+		Removed:    stats.Removed,
+		Retained:   stats.Kept,
+		FreedBytes: stats.FreedBytes,
+	}
+	if rotateErr != nil {
+		n = ErrorNotification{
+			Status:  "error",
+			Message: rotateErr.Error(),
+		}
+	}
+
+	body, err := json.Marshal(n)
+	if err != nil {
+		fmt.Printf("warning: could not encode notification: %v\n", err)
+		return
+	}
+
+	client := &http.Client{Timeout: notifyTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Printf("warning: could not send notification: %v\n", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		fmt.Printf("warning: notification failed with status %d\n", resp.StatusCode)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Prune removes all symlinks of the destination directory whose target does not exist,
+// e.g. after backups were deleted manually. It returns the number of removed symlinks.
+// In dry mode the symlinks are only printed.
+func (r *Rotator) Prune() (int, error) {
+	files, err := os.ReadDir(r.DestinationDir)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, file := range files {
+		if file.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+
+		linkPath := r.DestinationDir + file.Name()
+		target, err := os.Readlink(linkPath)
+		if err != nil {
+			return removed, err
+		}
+		// relative targets are resolved from the directory of the link
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(r.DestinationDir, target)
+		}
+		if _, err := os.Lstat(target); err == nil || !os.IsNotExist(err) {
+			continue
+		}
+
+		if r.Dry {
+			fmt.Println("DryRun: remove", linkPath, "->", target)
+		} else if err := os.Remove(linkPath); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// pruneCommand removes dangling symlinks from the destination directory.
+func pruneCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "prune",
+		Usage: "Remove symlinks of the destination directory whose backup does not exist anymore",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "destination",
+				Usage:    "Destination directory",
+				Required: true,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			rotator := newRotator(c, "", c.String("destination"))
+
+			removed, err := rotator.Prune()
+			if err != nil {
+				return fmt.Errorf("error pruning %s: %w", rotator.DestinationDir, err)
+			}
+
+			if rotator.Dry {
+				fmt.Printf("DryRun: %d broken symlinks would be removed\n", removed)
+			} else {
+				fmt.Printf("Removed %d broken symlinks\n", removed)
+			}
+			return nil
+		},
+	}
+}
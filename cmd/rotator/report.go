@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// formatBytes formats the size in a human readable way, e.g. 1.5 MiB.
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// formatAge formats the age in days and hours, e.g. 3d 4h.
+func formatAge(age time.Duration) string {
+	hours := int(age.Hours())
+	return fmt.Sprintf("%dd %dh", hours/24, hours%24)
+}
+
+// DryRunReport returns a human readable plan of the rotation without touching any file.
+func (r *Rotator) DryRunReport() string {
+	r.selectFiles()
+	remove, protected := r.unselected()
+
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "Files to keep:")
+	tags := r.selectedTags()
+	for _, backup := range r.FoundFiles {
+		if len(tags[backup.Name]) == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", backup.Name, formatBytes(backup.SizeBytes), formatAge(time.Since(backup.Time)), strings.Join(tags[backup.Name], ","))
+	}
+	for _, backup := range protected {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", backup.Name, formatBytes(backup.SizeBytes), formatAge(time.Since(backup.Time)), "protected")
+	}
+
+	fmt.Fprintln(tw, "\nFiles to link as:")
+	linked := make(map[string]bool)
+	for _, backup := range r.SelectedFiles {
+		for _, tag := range r.linkTags(tags[backup.Name]) {
+			link := tag + "-" + backup.Name
+			if linked[link] {
+				continue
+			}
+			linked[link] = true
+			fmt.Fprintf(tw, "  %s\t-> %s\n", r.DestinationDir+link, r.SourceDir+backup.Name)
+		}
+	}
+
+	fmt.Fprintln(tw, "\nFiles to remove:")
+	for _, backup := range remove {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t\n", backup.Name, formatBytes(backup.SizeBytes), formatAge(time.Since(backup.Time)))
+	}
+
+	tw.Flush()
+
+	return sb.String()
+}
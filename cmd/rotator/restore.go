@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Restore copies the found backup with the given name to destPath or links it if RestoreLink is set.
+// If destPath is a directory, the backup is placed inside it using its original name.
+// Existing files are never overwritten.
+func (r *Rotator) Restore(name, destPath string) error {
+	var backup *BackupFile
+	for i := range r.FoundFiles {
+		if r.FoundFiles[i].Name == name {
+			backup = &r.FoundFiles[i]
+			break
+		}
+	}
+	if backup == nil {
+		return fmt.Errorf("backup %s not found in %s", name, r.SourceDir)
+	}
+
+	if info, err := os.Stat(destPath); err == nil && info.IsDir() {
+		destPath = filepath.Join(destPath, backup.Name)
+	}
+	if _, err := os.Lstat(destPath); err == nil {
+		return fmt.Errorf("restore target %s already exists", destPath)
+	}
+
+	srcPath, err := filepath.Abs(r.SourceDir + backup.Name)
+	if err != nil {
+		return err
+	}
+
+	if r.Dry {
+		fmt.Println("DryRun: restore", srcPath, "to", destPath)
+		return nil
+	}
+
+	if r.RestoreLink {
+		return os.Symlink(srcPath, destPath)
+	}
+
+	if r.EntryType == "directory" {
+		return fmt.Errorf("copying backup directories is not supported, use --link")
+	}
+
+	return copyFile(srcPath, destPath)
+}
+
+// copyFile copies the content and permissions of the regular file src to dst.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// restoreCommand restores a single backup of the source directory.
+func restoreCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "restore",
+		Usage: "Copy or link a backup of the source directory to the given destination",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Usage:    "Name of the backup, e.g. 2024-01-15T03-00-00.sql.gz",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "dest",
+				Usage:    "Target file or directory",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "link",
+				Usage: "Create a symlink instead of a copy",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.String("source") == "" {
+				return fmt.Errorf("no source directory given")
+			}
+
+			rotator := newRotator(c, c.String("source"), "")
+			rotator.RestoreLink = c.Bool("link")
+
+			if _, err := rotator.Read(); err != nil {
+				return err
+			}
+
+			return rotator.Restore(c.String("name"), c.String("dest"))
+		},
+	}
+}
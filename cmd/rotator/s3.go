@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Destination uploads the selected backups to a S3 compatible bucket.
+// The credentials are read from the environment or the shared AWS config.
+type S3Destination struct {
+	Bucket string
+	Prefix string
+	Dry    bool
+
+	client   *s3.Client
+	uploader *manager.Uploader
+}
+
+// NewS3Destination creates a destination for the bucket in region.
+func NewS3Destination(ctx context.Context, bucket, prefix, region string, dry bool) (*S3Destination, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("error loading aws config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg)
+
+	return &S3Destination{
+		Bucket: bucket,
+		Prefix: prefix,
+		Dry:    dry,
+		client: client,
+		// uploads larger than the part size are split, a single PutObject is limited to 5 GB
+		uploader: manager.NewUploader(client),
+	}, nil
+}
+
+// key returns the object key of name below Prefix.
+func (d *S3Destination) key(name string) string {
+	return path.Join(d.Prefix, name)
+}
+
+// Put uploads the content of r as name. Backup files which already exist
+// with the same size are not uploaded again.
+func (d *S3Destination) Put(name string, r io.Reader) error {
+	if f, ok := r.(*os.File); ok {
+		exists, err := d.exists(name, f)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+	}
+
+	if d.Dry {
+		fmt.Printf("DryRun: put s3://%s/%s\n", d.Bucket, d.key(name))
+		return nil
+	}
+
+	_, err := d.uploader.Upload(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(d.key(name)),
+		Body:   r,
+	})
+	if err != nil {
+		return fmt.Errorf("error uploading %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// exists reports whether the object name exists with the size of f.
+func (d *S3Destination) exists(name string, f *os.File) (bool, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	head, err := d.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(d.key(name)),
+	})
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking %s: %w", name, err)
+	}
+
+	return aws.ToInt64(head.ContentLength) == info.Size(), nil
+}
+
+// List returns the names of all objects below Prefix.
+func (d *S3Destination) List() ([]string, error) {
+	prefix := ""
+	if d.Prefix != "" {
+		prefix = strings.TrimSuffix(d.Prefix, "/") + "/"
+	}
+
+	names := make([]string, 0)
+	paginator := s3.NewListObjectsV2Paginator(d.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(d.Bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("error listing s3://%s/%s: %w", d.Bucket, prefix, err)
+		}
+		for _, object := range page.Contents {
+			name := strings.TrimPrefix(aws.ToString(object.Key), prefix)
+			if name != "" && !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// Delete removes the object name, missing objects are ignored by S3.
+func (d *S3Destination) Delete(name string) error {
+	if d.Dry {
+		fmt.Printf("DryRun: delete s3://%s/%s\n", d.Bucket, d.key(name))
+		return nil
+	}
+
+	_, err := d.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(d.Bucket),
+		Key:    aws.String(d.key(name)),
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting %s: %w", name, err)
+	}
+
+	return nil
+}
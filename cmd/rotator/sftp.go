@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTPDestination uploads the selected backups to a directory on a SFTP server.
+// The connection is opened once by NewSFTPDestination and reused until Close.
+type SFTPDestination struct {
+	Host string
+	Path string
+	Dry  bool
+
+	ssh    *ssh.Client
+	client *sftp.Client
+}
+
+// NewSFTPDestination connects to host as user authenticated by the private key in keyFile.
+// The host key is verified against ~/.ssh/known_hosts.
+func NewSFTPDestination(host, user, keyFile, dir string, dry bool) (*SFTPDestination, error) {
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing private key %s: %w", keyFile, err)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	hostKeys, err := knownhosts.New(path.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading known hosts: %w", err)
+	}
+
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+
+	conn, err := ssh.Dial("tcp", host, &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeys,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %w", host, err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error starting sftp session: %w", err)
+	}
+
+	return &SFTPDestination{
+		Host:   host,
+		Path:   dir,
+		Dry:    dry,
+		ssh:    conn,
+		client: client,
+	}, nil
+}
+
+// Put uploads the content of r as name. Backup files which already exist with the same size
+// are not uploaded again. The content is uploaded to a temporary file which is renamed
+// when complete, so an interrupted upload does not leave a truncated backup.
+func (d *SFTPDestination) Put(name string, r io.Reader) error {
+	remotePath := path.Join(d.Path, name)
+	if f, ok := r.(*os.File); ok {
+		exists, err := d.exists(remotePath, f)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return nil
+		}
+	}
+
+	if d.Dry {
+		fmt.Printf("DryRun: put sftp://%s%s\n", d.Host, remotePath)
+		return nil
+	}
+
+	tmpPath := remotePath + ".part"
+	f, err := d.client.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("error creating %s: %w", tmpPath, err)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		d.client.Remove(tmpPath)
+		return fmt.Errorf("error uploading %s: %w", remotePath, err)
+	}
+	if err := f.Close(); err != nil {
+		d.client.Remove(tmpPath)
+		return fmt.Errorf("error uploading %s: %w", remotePath, err)
+	}
+
+	if err := d.client.PosixRename(tmpPath, remotePath); err != nil {
+		d.client.Remove(tmpPath)
+		return fmt.Errorf("error renaming %s: %w", tmpPath, err)
+	}
+
+	return nil
+}
+
+// exists reports whether the file at remotePath exists with the size of f.
+func (d *SFTPDestination) exists(remotePath string, f *os.File) (bool, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+
+	remote, err := d.client.Stat(remotePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking %s: %w", remotePath, err)
+	}
+
+	return remote.Mode().IsRegular() && remote.Size() == info.Size(), nil
+}
+
+// List returns the names of the files in Path.
+func (d *SFTPDestination) List() ([]string, error) {
+	files, err := d.client.ReadDir(d.Path)
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %w", d.Path, err)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.Mode().IsRegular() {
+			names = append(names, file.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// Delete removes the file name, missing files are ignored.
+func (d *SFTPDestination) Delete(name string) error {
+	remotePath := path.Join(d.Path, name)
+	if d.Dry {
+		fmt.Printf("DryRun: delete sftp://%s%s\n", d.Host, remotePath)
+		return nil
+	}
+
+	if err := d.client.Remove(remotePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error deleting %s: %w", remotePath, err)
+	}
+
+	return nil
+}
+
+// Close closes the connection to the server.
+func (d *SFTPDestination) Close() error {
+	d.client.Close()
+	return d.ssh.Close()
+}
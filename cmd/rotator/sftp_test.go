@@ -0,0 +1,114 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/sftp"
+)
+
+// newTestSFTPDestination returns a destination connected to an in-memory SFTP server.
+func newTestSFTPDestination(t *testing.T) *SFTPDestination {
+	t.Helper()
+
+	clientReader, serverWriter := io.Pipe()
+	serverReader, clientWriter := io.Pipe()
+
+	server := sftp.NewRequestServer(struct {
+		io.Reader
+		io.WriteCloser
+	}{serverReader, serverWriter}, sftp.InMemHandler())
+	go server.Serve()
+
+	client, err := sftp.NewClientPipe(clientReader, clientWriter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		// the client waits for the end of the server's output on Close
+		serverWriter.Close()
+		client.Close()
+		server.Close()
+	})
+
+	return &SFTPDestination{Host: "test", Path: "/", client: client}
+}
+
+func TestSFTPDestinationPut(t *testing.T) {
+	d := newTestSFTPDestination(t)
+
+	local := filepath.Join(t.TempDir(), "backup.sql.gz")
+	if err := os.WriteFile(local, []byte("backup"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	put := func() {
+		t.Helper()
+		f, err := os.Open(local)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := d.Put("daily-backup.sql.gz", f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	put()
+	names, err := d.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != "daily-backup.sql.gz" {
+		t.Fatalf("files = %v, want only the renamed upload", names)
+	}
+
+	// an existing file of the same size is not uploaded again
+	remote, err := d.client.Create("/daily-backup.sql.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote.Write([]byte("BACKUP"))
+	remote.Close()
+
+	put()
+	remote, err = d.client.Open("/daily-backup.sql.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+	if content, _ := io.ReadAll(remote); string(content) != "BACKUP" {
+		t.Errorf("content = %q, existing file was uploaded again", content)
+	}
+}
+
+// failingReader fails after the first read, like a dropped connection.
+type failingReader struct {
+	read bool
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.read {
+		return 0, io.ErrUnexpectedEOF
+	}
+	r.read = true
+	return copy(p, "partial"), nil
+}
+
+func TestSFTPDestinationPutInterrupted(t *testing.T) {
+	d := newTestSFTPDestination(t)
+
+	if err := d.Put("daily-backup.sql.gz", &failingReader{}); err == nil {
+		t.Fatal("expected error for interrupted upload")
+	}
+
+	names, err := d.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 0 {
+		t.Errorf("files = %v, want no partial upload", names)
+	}
+}
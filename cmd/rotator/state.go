@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// State is the rotation history persisted in the state file.
+type State struct {
+	LastRun       time.Time           `json:"lastRun"`
+	RemovedFiles  []string            `json:"removedFiles"`
+	RetainedFiles map[string][]string `json:"retainedFiles"`
+}
+
+// readState reads the state file. A missing file results in an empty state.
+func readState(path string) (*State, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var state State
+	if err := json.NewDecoder(file).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to decode state file %s: %w", path, err)
+	}
+
+	return &state, nil
+}
+
+// checkState flags all files which were removed by the previous run but still exist.
+func (r *Rotator) checkState() error {
+	state, err := readState(r.StateFile)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[string]bool)
+	for _, backup := range r.FoundFiles {
+		found[backup.Name] = true
+	}
+
+	r.Leftovers = make([]string, 0)
+	for _, name := range state.RemovedFiles {
+		if found[name] {
+			r.Leftovers = append(r.Leftovers, name)
+			fmt.Println("WARNING: file was removed by the last run but still exists:", r.SourceDir+name)
+		}
+	}
+
+	return nil
+}
+
+// writeState persists the result of the current rotation to the state file.
+func (r *Rotator) writeState() error {
+	state := State{
+		LastRun:       time.Now(),
+		RemovedFiles:  r.Removed,
+		RetainedFiles: r.selectedTags(),
+	}
+
+	file, err := os.Create(r.StateFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(state)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RotationStats summarizes the last rotation.
+type RotationStats struct {
+	Total      int   `json:"total"`
+	Kept       int   `json:"kept"`
+	Removed    int   `json:"removed"`
+	Linked     int   `json:"linked"`
+	FreedBytes int64 `json:"freedBytes"`
+
+	// DryRemoved and DryFreedBytes count the backups a dry run would remove,
+	// Removed and FreedBytes stay 0 in dry runs.
+	DryRemoved    int   `json:"dryRemoved"`
+	DryFreedBytes int64 `json:"dryFreedBytes"`
+
+	// Duration is encoded as seconds in JSON.
+	Duration time.Duration `json:"duration"`
+}
+
+// MarshalJSON encodes the stats with the duration in seconds.
+func (s RotationStats) MarshalJSON() ([]byte, error) {
+	type stats RotationStats
+	return json.Marshal(struct {
+		stats
+		Duration float64 `json:"duration"`
+	}{stats(s), s.Duration.Seconds()})
+}
+
+// Stats returns the summary of the last rotation.
+func (r *Rotator) Stats() RotationStats {
+	return r.stats
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+var statsBackups = []string{
+	"2024-01-14T03-00-00.sql.gz",
+	"2024-01-15T03-00-00.sql.gz",
+	"2024-01-16T03-00-00.sql.gz",
+	"2024-01-17T03-00-00.sql.gz",
+}
+
+// rotateForStats rotates the statsBackups keeping two of them and returns the stats.
+func rotateForStats(t *testing.T, dry bool) RotationStats {
+	t.Helper()
+
+	r := newTestRotator(t, statsBackups...)
+	r.KeepDays = 2
+	r.Dry = dry
+	if _, err := r.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	return r.Stats()
+}
+
+func TestStats(t *testing.T) {
+	stats := rotateForStats(t, false)
+
+	size := int64(len(statsBackups[0]))
+	want := RotationStats{Total: 4, Kept: 2, Removed: 2, Linked: 2, FreedBytes: 2 * size}
+	stats.Duration = 0
+	if stats != want {
+		t.Errorf("stats = %+v, want %+v", stats, want)
+	}
+}
+
+func TestStatsDryRun(t *testing.T) {
+	stats := rotateForStats(t, true)
+
+	size := int64(len(statsBackups[0]))
+	if stats.Removed != 0 || stats.FreedBytes != 0 {
+		t.Errorf("dry run removed %d backups, freed %d bytes", stats.Removed, stats.FreedBytes)
+	}
+	if stats.DryRemoved != 2 || stats.DryFreedBytes != 2*size {
+		t.Errorf("dry run would remove %d backups, free %d bytes, want 2 and %d", stats.DryRemoved, stats.DryFreedBytes, 2*size)
+	}
+	if stats.Kept != 2 {
+		t.Errorf("kept = %d, want 2", stats.Kept)
+	}
+}
+
+func TestStatsJSON(t *testing.T) {
+	data, err := json.Marshal(RotationStats{Total: 3, Duration: 1500 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["duration"] != 1.5 {
+		t.Errorf("duration = %v, want 1.5 seconds", got["duration"])
+	}
+	if got["total"] != 3.0 {
+		t.Errorf("total = %v, want 3", got["total"])
+	}
+}
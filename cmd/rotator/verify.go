@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VerifyError describes a broken link of a selected backup file.
+// Errors without Tag are checksum mismatches of the backup itself.
+type VerifyError struct {
+	File BackupFile
+	Tag  string
+	Err  error
+}
+
+func (e VerifyError) Error() string {
+	if e.Tag == "" {
+		return fmt.Sprintf("%s: %v", e.File.Name, e.Err)
+	}
+
+	return fmt.Sprintf("%s-%s: %v", e.Tag, e.File.Name, e.Err)
+}
+
+// verifyLink checks that the link exists and its target can be opened.
+func verifyLink(linkPath string) error {
+	info, err := os.Lstat(linkPath)
+	if err != nil {
+		return err
+	}
+
+	target := linkPath
+	if info.Mode()&os.ModeSymlink != 0 {
+		if target, err = os.Readlink(linkPath); err != nil {
+			return err
+		}
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(linkPath), target)
+	}
+
+	f, err := os.Open(target)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// Verify checks every symlink of the selected files and returns all broken ones.
+// Like link, only the biggest tag is checked with SingleLink.
+// Broken symlinks are removed, in dry run mode the results are only printed.
+// Backups with a checksum are compared against their current content.
+func (r *Rotator) Verify() []VerifyError {
+	errs := make([]VerifyError, 0)
+	tags := r.selectedTags()
+	checked := make(map[string]bool)
+	for _, file := range r.SelectedFiles {
+		// backups with multiple tags are selected multiple times
+		if checked[file.Name] {
+			continue
+		}
+		checked[file.Name] = true
+
+		if err := r.verifyChecksum(file); err != nil {
+			errs = append(errs, VerifyError{File: file, Err: err})
+		}
+
+		for _, tag := range r.linkTags(tags[file.Name]) {
+			linkPath := r.DestinationDir + tag + "-" + file.Name
+			err := verifyLink(linkPath)
+			if r.Dry {
+				if err != nil {
+					fmt.Println("DryRun: verify failed", linkPath, err)
+				} else {
+					fmt.Println("DryRun: verify ok", linkPath)
+				}
+			}
+			if err == nil {
+				continue
+			}
+
+			errs = append(errs, VerifyError{File: file, Tag: tag, Err: err})
+			if !r.Dry {
+				if err := os.Remove(linkPath); err != nil && !os.IsNotExist(err) {
+					fmt.Printf("error removing broken link %s: %v\n", linkPath, err)
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+// ValidateLinks checks that the destination directory contains a link for every tag
+// of the selected backups and returns an error naming the tags with missing links.
+// Remote destinations and dry runs, which create no links, are not validated.
+func (r *Rotator) ValidateLinks() error {
+	if r.Destination != nil || r.Dry {
+		return nil
+	}
+
+	expected := make(map[string]int)
+	for _, tags := range r.selectedTags() {
+		for _, tag := range r.linkTags(tags) {
+			expected[tag]++
+		}
+	}
+
+	files, err := os.ReadDir(r.DestinationDir)
+	if err != nil {
+		return err
+	}
+	found := make(map[string]int)
+	for _, file := range files {
+		if tag, _, ok := strings.Cut(file.Name(), "-"); ok {
+			found[tag]++
+		}
+	}
+
+	missing := make([]string, 0)
+	for _, tag := range r.tagPriority() {
+		if found[tag] < expected[tag] {
+			missing = append(missing, fmt.Sprintf("%s: %d of %d", tag, found[tag], expected[tag]))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing links in %s: %s", r.DestinationDir, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
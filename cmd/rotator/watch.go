@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/urfave/cli/v2"
+)
+
+// watchDebounce is the time without new events before a rotation is triggered,
+// so a backup which is still being written does not trigger multiple rotations.
+const watchDebounce = 5 * time.Second
+
+// watch runs the rotation once and again whenever a new backup file appears
+// in the source directory until SIGTERM or SIGINT is received.
+func watch(c *cli.Context, rotator *Rotator) error {
+	res, err := rotator.patterns()
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(rotator.SourceDir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", rotator.SourceDir, err)
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	if err := rotate(c, rotator); err != nil {
+		fmt.Println("error rotating:", err)
+	}
+
+	fmt.Println("Watching", rotator.SourceDir)
+
+	trigger := make(chan struct{}, 1)
+	debounce := time.AfterFunc(watchDebounce, func() {
+		select {
+		case trigger <- struct{}{}:
+		default:
+		}
+	})
+	debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			debounce.Stop()
+			fmt.Println("Stopping watcher")
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+				continue
+			}
+
+			name := filepath.Base(event.Name)
+			for _, re := range res {
+				if re.MatchString(name) {
+					debounce.Reset(watchDebounce)
+					break
+				}
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Println("error watching:", err)
+
+		case <-trigger:
+			// the rotation runs synchronously, a signal is handled after it finished
+			if err := rotate(c, rotator); err != nil {
+				fmt.Println("error rotating:", err)
+			}
+		}
+	}
+}
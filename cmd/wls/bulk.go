@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/spf13/viper"
+)
+
+// handleBulkLog stores a markdown log with multiple days, e.g. to catch up after a week off.
+// Every day is parsed and stored independently, so the valid days are stored if another
+// day fails. Days without entries are not stored and reported as error.
+// The response maps each date to "ok" or the error of the day.
+func (srv *Server) handleBulkLog(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("bulk log triggered")
+
+	body, ok := readLogBody(w, r)
+	if !ok {
+		return
+	}
+
+	dates, blocks := splitDays(body)
+	if len(dates) == 0 {
+		writeError(w, http.StatusBadRequest, "No date headings like # 2024-01-15 found")
+		return
+	}
+
+	pattern := srv.entryPattern()
+	results := make(map[string]string, len(dates))
+	for _, date := range dates {
+		entries, err := parseEntries(pattern, blocks[date])
+		if err != nil {
+			results[date] = "error: " + err.Error()
+			slog.Error("Failed to parse entries", "date", date, "error", err)
+			continue
+		}
+		if len(entries) == 0 {
+			results[date] = "error: no entries found"
+			slog.Error("No entries found", "date", date)
+			continue
+		}
+
+		filePath, err := storeEntries(viper.GetString("wls.app.dataDir"), date, entries)
+		if err != nil {
+			results[date] = "error: " + err.Error()
+			slog.Error("Failed to store entries", "date", date, "error", err)
+			continue
+		}
+
+		results[date] = "ok"
+		slog.Info("Entries successfully written to file", "file", filePath)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestHandleBulkLog(t *testing.T) {
+	srv := newTestServer(t)
+
+	body := "# 2024-01-15\n" +
+		"  ▶ | 1 | a1 | #issue/1 #action/dev | moved to #2024-01-17 |\n" +
+		"# 2024-01-16\n" +
+		"nothing logged\n"
+	req := httptest.NewRequest(http.MethodPost, "/log/bulk", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.handleBulkLog(rec, req)
+
+	var results map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+
+	// the tag within the note is no date heading
+	if len(results) != 2 {
+		t.Fatalf("results = %v, want one result per heading", results)
+	}
+	if results["2024-01-15"] != "ok" {
+		t.Errorf("2024-01-15: %q, want ok", results["2024-01-15"])
+	}
+	if !strings.HasPrefix(results["2024-01-16"], "error:") {
+		t.Errorf("2024-01-16: %q, want an error for the day without entries", results["2024-01-16"])
+	}
+
+	path, err := entryFilePath(viper.GetString("wls.app.dataDir"), "2024-01-16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readEntries(path); err == nil {
+		t.Error("the day without entries was stored")
+	}
+}
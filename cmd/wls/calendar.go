@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// icsTimeFormat is the iCalendar format of a local date-time without time zone.
+const icsTimeFormat = "20060102T150405"
+
+// icsEscaper escapes the characters with a special meaning in iCalendar text values.
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// calendarEvent returns the VEVENT of entry starting at start.
+func calendarEvent(entry TimeEntry, date string, index int, start time.Time) string {
+	end := start.Add(time.Duration(entry.Hours * float64(time.Hour)))
+
+	summary := entry.Note
+	if issue := findInTags(entry.Tags, "issue"); issue != "" {
+		summary = "#" + issue + " " + summary
+	}
+
+	tags := make([]string, len(entry.Tags))
+	for i, tag := range entry.Tags {
+		tags[i] = tag.Name + "/" + tag.Value
+	}
+	description := strconv.FormatFloat(entry.Hours, 'f', 2, 64) + "h " + strings.Join(tags, " ")
+
+	lines := []string{
+		"BEGIN:VEVENT",
+		fmt.Sprintf("UID:%s-%d@wls", date, index),
+		"DTSTAMP:" + time.Now().UTC().Format(icsTimeFormat) + "Z",
+		"DTSTART:" + start.Format(icsTimeFormat),
+		"DTEND:" + end.Format(icsTimeFormat),
+		"SUMMARY:" + icsEscaper.Replace(summary),
+		"DESCRIPTION:" + icsEscaper.Replace(description),
+		"END:VEVENT",
+	}
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// calendar returns the entries of the from and to parameters as iCalendar events.
+// The events of a day are stacked consecutively starting at wls.calendar.startHour.
+func (srv *Server) calendar(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("calendar triggered")
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	startHour := viper.GetInt("wls.calendar.startHour")
+
+	var sb strings.Builder
+	sb.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//b1tray3r//wls//EN\r\n")
+	err = walkDateRange(viper.GetString("wls.app.dataDir"), from, to, func(date string, entries []TimeEntry) error {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return err
+		}
+
+		start := day.Add(time.Duration(startHour) * time.Hour)
+		for i, entry := range entries {
+			sb.WriteString(calendarEvent(entry, date, i, start))
+			start = start.Add(time.Duration(entry.Hours * float64(time.Hour)))
+		}
+		return nil
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to read entries")
+		slog.Error("Failed to read entries", "error", err)
+		return
+	}
+	sb.WriteString("END:VCALENDAR\r\n")
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="wls.ics"`)
+	w.Write([]byte(sb.String()))
+}
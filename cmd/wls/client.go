@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/b1tray3r/go/internal/redmine"
+	"github.com/spf13/viper"
+)
+
+// newRedmineClient creates a redmine client from the wls.redmine settings of v.
+// Only the wls settings are used if the config file contains other tools as well.
+func newRedmineClient(v *viper.Viper) (*redmine.Client, error) {
+	return redmine.NewClientFromConfig(v, []string{"wls.redmine."}, redmine.WithTracing(slog.Default()))
+}
+
+// redmineClient returns the redmine client shared by all handlers.
+// It is created on first use, so the server starts without redmine credentials.
+func (srv *Server) redmineClient() (*redmine.Client, error) {
+	srv.clientMu.Lock()
+	defer srv.clientMu.Unlock()
+
+	if srv.client == nil {
+		rc, err := newRedmineClient(viper.GetViper())
+		if err != nil {
+			return nil, err
+		}
+		srv.client = rc
+	}
+
+	return srv.client, nil
+}
+
+// reloadClient replaces the shared redmine client by one created from the settings of v.
+// The current client is kept if the settings are invalid.
+func (srv *Server) reloadClient(v *viper.Viper) error {
+	rc, err := newRedmineClient(v)
+	if err != nil {
+		return fmt.Errorf("failed to create Redmine client: %w", err)
+	}
+
+	srv.clientMu.Lock()
+	srv.client = rc
+	srv.clientMu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestRedmineClientIsShared(t *testing.T) {
+	t.Setenv("REDMINE_URL", "http://redmine.example.com")
+	t.Setenv("REDMINE_API_KEY", "key")
+
+	srv, err := NewServer(&BasicAuth{Username: "admin", Secret: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := srv.redmineClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := srv.redmineClient(); second != first {
+		t.Error("redmineClient created a new client for the second request")
+	}
+
+	if err := srv.reloadClient(viper.New()); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, _ := srv.redmineClient(); reloaded == first {
+		t.Error("reloadClient kept the old client")
+	}
+}
+
+func TestReloadClientKeepsClientOnError(t *testing.T) {
+	t.Setenv("REDMINE_URL", "")
+	t.Setenv("REDMINE_API_KEY", "")
+
+	srv, err := NewServer(&BasicAuth{Username: "admin", Secret: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := viper.New()
+	config.Set("wls.redmine.url", "http://redmine.example.com")
+	config.Set("wls.redmine.key", "key")
+	if err := srv.reloadClient(config); err != nil {
+		t.Fatal(err)
+	}
+	current, _ := srv.redmineClient()
+
+	if err := srv.reloadClient(viper.New()); err == nil {
+		t.Fatal("reloadClient accepted a config without credentials")
+	}
+	if rc, _ := srv.redmineClient(); rc != current {
+		t.Error("failed reload replaced the client")
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// configCheckTimeout is the maximum time to wait for redmine during the config check.
+const configCheckTimeout = 5 * time.Second
+
+// requiredConfigKeys must be set to start the server and sync entries.
+var requiredConfigKeys = []string{
+	"wls.auth.username",
+	"wls.auth.password",
+	"wls.redmine.url",
+	"wls.redmine.key",
+}
+
+// configCheck is the result of a single check of runConfigCheck.
+type configCheck struct {
+	name string
+	err  error
+}
+
+// checkRedmine creates a redmine client from the config and checks that the API key is accepted.
+func checkRedmine() error {
+	rc, err := newRedmineClient(viper.GetViper())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), configCheckTimeout)
+	defer cancel()
+
+	return rc.Ping(ctx)
+}
+
+// runConfigCheck checks the configuration and writes a report of all checks to w.
+// An error is returned if any check failed.
+func runConfigCheck(w io.Writer) error {
+	checks := make([]configCheck, 0)
+
+	if path := viper.ConfigFileUsed(); path != "" {
+		checks = append(checks, configCheck{"config file " + path, nil})
+	} else {
+		checks = append(checks, configCheck{"config file", fmt.Errorf("not found")})
+	}
+
+	missing := false
+	for _, key := range requiredConfigKeys {
+		var err error
+		if !viper.IsSet(key) || viper.GetString(key) == "" {
+			err = fmt.Errorf("not set")
+			missing = true
+		}
+		checks = append(checks, configCheck{key, err})
+	}
+
+	checks = append(checks, configCheck{"wls.data.layout", validateLayout(dataLayout())})
+	_, err := compileEntryPattern(viper.GetViper())
+	checks = append(checks, configCheck{"wls.parse.entryRegex", err})
+
+	// without credentials the redmine check would only repeat the missing keys
+	if !missing {
+		checks = append(checks, configCheck{"redmine connection", checkRedmine()})
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if check.err != nil {
+			failed++
+			fmt.Fprintf(w, "[FAIL] %s: %v\n", check.name, check.err)
+			continue
+		}
+		fmt.Fprintf(w, "[ OK ] %s\n", check.name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// csvHeader are the column names of the CSV export.
+var csvHeader = []string{"date", "id", "issue", "activity", "hours", "tags", "note", "synced"}
+
+// csvRecord maps entry of date to the columns of csvHeader.
+func csvRecord(date string, entry TimeEntry) []string {
+	tags := make([]string, len(entry.Tags))
+	for i, tag := range entry.Tags {
+		tags[i] = tag.Name + "/" + tag.Value
+	}
+
+	return []string{
+		date,
+		entry.ID,
+		findInTags(entry.Tags, "issue"),
+		findInTags(entry.Tags, "action"),
+		strconv.FormatFloat(entry.Hours, 'f', 2, 64),
+		strings.Join(tags, " "),
+		entry.Note,
+		strconv.FormatBool(entry.Synced),
+	}
+}
+
+// writeCSV writes the entries between from and to stored in dataDir as CSV to w.
+func writeCSV(w io.Writer, dataDir string, from, to time.Time) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	err := walkDateRange(dataDir, from, to, func(date string, entries []TimeEntry) error {
+		for _, entry := range entries {
+			if err := cw.Write(csvRecord(date, entry)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// runExportCSV writes the entries of the date range given in args as CSV to a file or stdout.
+func runExportCSV(args []string) error {
+	fset := flag.NewFlagSet("export-csv", flag.ExitOnError)
+	fromFlag := fset.String("from", "", "first date to export, e.g. 2024-01-01")
+	toFlag := fset.String("to", "", "last date to export, e.g. 2024-03-31")
+	out := fset.String("out", "", "target file, stdout if empty")
+	fset.Parse(args)
+
+	from, err := time.Parse("2006-01-02", *fromFlag)
+	if err != nil {
+		return fmt.Errorf("--from must be a date like 2024-01-01")
+	}
+	to, err := time.Parse("2006-01-02", *toFlag)
+	if err != nil {
+		return fmt.Errorf("--to must be a date like 2024-03-31")
+	}
+	if to.Before(from) {
+		return fmt.Errorf("--to must not be before --from")
+	}
+
+	dataDir := viper.GetString("wls.app.dataDir")
+	if *out == "" {
+		return writeCSV(os.Stdout, dataDir, from, to)
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := writeCSV(file, dataDir, from, to); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// idempotencyCacheSize is the number of responses kept for replay.
+const idempotencyCacheSize = 100
+
+// idempotencyTTL is the duration a response is replayed for the same key.
+const idempotencyTTL = 24 * time.Hour
+
+// idempotentResponse is the recorded response of a request with an Idempotency-Key.
+type idempotentResponse struct {
+	key     string
+	created time.Time
+	done    bool
+
+	status      int
+	contentType string
+	body        []byte
+}
+
+// idempotencyCache is a LRU cache of the responses by Idempotency-Key.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// begin returns a copy of the response recorded for key. If there is none, key is marked as in flight.
+// A copy is returned as finish updates the recorded response concurrently.
+func (c *idempotencyCache) begin(key string) (idempotentResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.order = list.New()
+		c.entries = make(map[string]*list.Element)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		resp := el.Value.(*idempotentResponse)
+		if time.Since(resp.created) < idempotencyTTL {
+			c.order.MoveToFront(el)
+			return *resp, true
+		}
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+
+	c.entries[key] = c.order.PushFront(&idempotentResponse{key: key, created: time.Now()})
+	for c.order.Len() > idempotencyCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*idempotentResponse).key)
+	}
+
+	return idempotentResponse{}, false
+}
+
+// finish records the response of key. Server errors are not recorded so the request can be retried.
+func (c *idempotencyCache) finish(key string, rec *responseRecorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	if rec.status >= http.StatusInternalServerError {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return
+	}
+
+	resp := el.Value.(*idempotentResponse)
+	resp.done = true
+	resp.status = rec.status
+	resp.contentType = rec.Header().Get("Content-Type")
+	resp.body = rec.body.Bytes()
+}
+
+// abort removes key if it is still in flight, e.g. because the handler panicked,
+// so the request can be retried.
+func (c *idempotencyCache) abort(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok || el.Value.(*idempotentResponse).done {
+		return
+	}
+	c.order.Remove(el)
+	delete(c.entries, key)
+}
+
+// responseRecorder passes the response through and keeps a copy of the status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	rec.status = status
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// idempotencyKey returns the cache key of the request or an empty string if it has no Idempotency-Key header.
+// The same header value may be used for requests to other endpoints.
+func idempotencyKey(r *http.Request) string {
+	header := r.Header.Get("Idempotency-Key")
+	if header == "" {
+		return ""
+	}
+
+	return r.Method + " " + r.URL.Path + " " + header
+}
+
+// withIdempotency is a middleware replaying the response of requests with a known Idempotency-Key header
+// to the same method and path. A request with a key which is still processed is rejected with 409 Conflict.
+func (srv *Server) withIdempotency(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := idempotencyKey(r)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if resp, ok := srv.idempotency.begin(key); ok {
+			if !resp.done {
+				writeError(w, http.StatusConflict, "Request with this Idempotency-Key is in progress")
+				return
+			}
+
+			slog.Debug("Replaying response", "idempotencyKey", key)
+			if resp.contentType != "" {
+				w.Header().Set("Content-Type", resp.contentType)
+			}
+			w.WriteHeader(resp.status)
+			w.Write(resp.body)
+			return
+		}
+
+		// a panicking handler must not block the key until the TTL expired
+		defer srv.idempotency.abort(key)
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		srv.idempotency.finish(key, rec)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// idempotentRequest sends a POST request to path with the Idempotency-Key header key.
+func idempotentRequest(handler http.HandlerFunc, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Idempotency-Key", key)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	return rec
+}
+
+// countingHandler returns a handler counting its calls in calls.
+func countingHandler(calls *atomic.Int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":200}`))
+	}
+}
+
+func TestWithIdempotencyConcurrentReplay(t *testing.T) {
+	srv := &Server{}
+	var calls atomic.Int64
+	handler := srv.withIdempotency(countingHandler(&calls))
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			rec := idempotentRequest(handler, "/log", "key")
+			if rec.Code != http.StatusOK && rec.Code != http.StatusConflict {
+				t.Errorf("status = %d, want 200 or 409", rec.Code)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("handler ran %d times, want once", n)
+	}
+}
+
+func TestWithIdempotencyKeyIncludesPath(t *testing.T) {
+	srv := &Server{}
+	var calls atomic.Int64
+	handler := srv.withIdempotency(countingHandler(&calls))
+
+	idempotentRequest(handler, "/log", "key")
+	idempotentRequest(handler, "/log/bulk", "key")
+	if n := calls.Load(); n != 2 {
+		t.Errorf("handler ran %d times, want once per path", n)
+	}
+}
+
+func TestWithIdempotencyTTL(t *testing.T) {
+	srv := &Server{}
+	var calls atomic.Int64
+	handler := srv.withIdempotency(countingHandler(&calls))
+
+	idempotentRequest(handler, "/log", "key")
+	idempotentRequest(handler, "/log", "key")
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("handler ran %d times before the TTL expired, want once", n)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/log", nil)
+	req.Header.Set("Idempotency-Key", "key")
+	srv.idempotency.mu.Lock()
+	el := srv.idempotency.entries[idempotencyKey(req)]
+	el.Value.(*idempotentResponse).created = time.Now().Add(-idempotencyTTL)
+	srv.idempotency.mu.Unlock()
+
+	idempotentRequest(handler, "/log", "key")
+	if n := calls.Load(); n != 2 {
+		t.Errorf("handler ran %d times after the TTL expired, want twice", n)
+	}
+}
+
+func TestWithIdempotencyEvictsLeastRecentlyUsed(t *testing.T) {
+	srv := &Server{}
+	var calls atomic.Int64
+	handler := srv.withIdempotency(countingHandler(&calls))
+
+	for i := range idempotencyCacheSize {
+		idempotentRequest(handler, "/log", strconv.Itoa(i))
+	}
+	// replaying key 0 makes key 1 the least recently used one
+	idempotentRequest(handler, "/log", "0")
+	idempotentRequest(handler, "/log", "new")
+	if n := calls.Load(); n != idempotencyCacheSize+1 {
+		t.Fatalf("handler ran %d times, want %d", n, idempotencyCacheSize+1)
+	}
+
+	idempotentRequest(handler, "/log", "0")
+	if n := calls.Load(); n != idempotencyCacheSize+1 {
+		t.Errorf("recently used key 0 was evicted")
+	}
+	idempotentRequest(handler, "/log", "1")
+	if n := calls.Load(); n != idempotencyCacheSize+2 {
+		t.Errorf("least recently used key 1 was not evicted")
+	}
+}
+
+func TestWithIdempotencyPanicReleasesKey(t *testing.T) {
+	srv := &Server{}
+	panicking := srv.withIdempotency(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/log", nil)
+	req.Header.Set("Idempotency-Key", "key")
+	func() {
+		defer func() { recover() }()
+		panicking(httptest.NewRecorder(), req)
+	}()
+
+	handler := srv.withIdempotency(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Errorf("status after panic = %d, want %d", rec.Code, http.StatusCreated)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/spf13/viper"
+)
+
+// importFileRegex matches the names of daily markdown logs.
+var importFileRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\.md$`)
+
+// importFile parses the markdown log at path with pattern, see parseEntries, and stores its entries in dataDir.
+// If dry is set the entries are only printed.
+func importFile(path, dataDir string, pattern *regexp.Regexp, dry bool) error {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	entries, err := parseEntries(pattern, string(body))
+	if err != nil {
+		return err
+	}
+
+	// files without date heading are named after their date
+	date := parseDate(string(body))
+	if date == "" {
+		date = filepath.Base(path)[:10]
+	}
+
+	if dry {
+		fmt.Printf("DryRun: %s: %d entries for %s\n", path, len(entries), date)
+		for _, entry := range entries {
+			fmt.Printf("  %.2fh %s %s\n", entry.Hours, entry.ID, entry.Note)
+		}
+		return nil
+	}
+
+	filePath, err := storeEntries(dataDir, date, entries)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d entries written to %s\n", path, len(entries), filePath)
+
+	return nil
+}
+
+// runImport imports all markdown logs named like 2024-01-15.md below the directory given in args.
+// Files which can not be imported are reported and skipped.
+func runImport(args []string) error {
+	fset := flag.NewFlagSet("import", flag.ExitOnError)
+	dry := fset.Bool("dry-run", false, "print the entries instead of writing them")
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "Usage: wls import [--dry-run] <directory>")
+		fset.PrintDefaults()
+	}
+	fset.Parse(args)
+
+	if fset.NArg() != 1 {
+		fset.Usage()
+		return fmt.Errorf("expected exactly one directory")
+	}
+
+	pattern, err := compileEntryPattern(viper.GetViper())
+	if err != nil {
+		return err
+	}
+
+	dataDir := viper.GetString("wls.app.dataDir")
+	failed := 0
+	err = filepath.WalkDir(fset.Arg(0), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !importFileRegex.MatchString(d.Name()) {
+			return nil
+		}
+
+		if err := importFile(path, dataDir, pattern, *dry); err != nil {
+			failed++
+			slog.Error("Failed to import file", "file", path, "error", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d files could not be imported", failed)
+	}
+
+	return nil
+}
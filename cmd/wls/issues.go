@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// openIssuesTTL is the duration the open issues of a time span are cached.
+const openIssuesTTL = 10 * time.Minute
+
+// OpenIssue is an issue referenced by entries which is not closed yet.
+type OpenIssue struct {
+	ID      int64  `json:"id"`
+	Subject string `json:"subject"`
+	Project string `json:"project"`
+	Status  string `json:"status"`
+}
+
+// openIssuesCache holds the open issues per number of days.
+type openIssuesCache struct {
+	mu      sync.Mutex
+	issues  map[int][]OpenIssue
+	fetched map[int]time.Time
+}
+
+// get returns the cached issues of days if they are not expired.
+func (c *openIssuesCache) get(days int) ([]OpenIssue, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.fetched[days]) > openIssuesTTL {
+		return nil, false
+	}
+
+	return c.issues[days], true
+}
+
+// set caches the issues of days.
+func (c *openIssuesCache) set(days int, issues []OpenIssue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.issues == nil {
+		c.issues = make(map[int][]OpenIssue)
+		c.fetched = make(map[int]time.Time)
+	}
+	c.issues[days] = issues
+	c.fetched[days] = time.Now()
+}
+
+// openIssues returns the open issues referenced by the entries of the last days parameter (30 by default).
+// Results with failed issue lookups are not cached, if all lookups failed it responds with 502.
+func (srv *Server) openIssues(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("open issues triggered")
+
+	days := 30
+	if d := r.URL.Query().Get("days"); d != "" {
+		var err error
+		if days, err = strconv.Atoi(d); err != nil || days < 1 {
+			writeError(w, http.StatusBadRequest, "days parameter must be a positive number")
+			return
+		}
+	}
+
+	if issues, ok := srv.openIssuesCache.get(days); ok {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(issues)
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -days)
+	issueIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	err := walkDateRange(viper.GetString("wls.app.dataDir"), from, to, func(date string, entries []TimeEntry) error {
+		for _, entry := range entries {
+			if issueID := findInTags(entry.Tags, "issue"); issueID != "" && !seen[issueID] {
+				seen[issueID] = true
+				issueIDs = append(issueIDs, issueID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to read entries")
+		slog.Error("Failed to read entries", "error", err)
+		return
+	}
+
+	rc, err := srv.redmineClient()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to create Redmine client")
+		slog.Error("Failed to create Redmine client", "error", err)
+		return
+	}
+
+	found, failed := fetchIssues(r.Context(), rc, issueIDs)
+	if failed > 0 && len(found) == 0 {
+		writeError(w, http.StatusBadGateway, "Failed to get issues from Redmine")
+		return
+	}
+
+	issues := make([]OpenIssue, 0)
+	for _, issue := range found {
+		if issue.Status.IsClosed {
+			continue
+		}
+		issues = append(issues, OpenIssue{
+			ID:      issue.ID,
+			Subject: issue.Subject,
+			Project: issue.Project.Name,
+			Status:  issue.Status.Name,
+		})
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].ID < issues[j].ID
+	})
+	// incomplete results are not cached so the next request retries the failed lookups
+	if failed == 0 {
+		srv.openIssuesCache.set(days, issues)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(issues)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// isoWeekToken is replaced by the ISO week number in the data layout,
+// e.g. 2006/W02 stores 2024-04-10 in 2024/W15. time.Format has no ISO week.
+// Together with the week, 2006 is the ISO year, so 2024-12-30 is stored in 2025/W01.
+const isoWeekToken = "W02"
+
+// yearToken is the year of the data layout.
+const yearToken = "2006"
+
+// dataLayout returns the time layout of the directories below wls.app.dataDir.
+func dataLayout() string {
+	return viper.GetString("wls.data.layout")
+}
+
+// formatLayout returns the directory of day in the given data layout.
+func formatLayout(layout string, day time.Time) string {
+	if !strings.Contains(layout, isoWeekToken) {
+		return day.Format(layout)
+	}
+
+	year, week := day.ISOWeek()
+	parts := strings.Split(layout, isoWeekToken)
+	for i, part := range parts {
+		years := strings.Split(part, yearToken)
+		for j, yearPart := range years {
+			years[j] = day.Format(yearPart)
+		}
+		parts[i] = strings.Join(years, strconv.Itoa(year))
+	}
+
+	return strings.Join(parts, fmt.Sprintf("W%02d", week))
+}
+
+// validateLayout checks that layout results in a relative directory below the data directory
+// and that a known date survives formatting and parsing the directory.
+func validateLayout(layout string) error {
+	known := time.Date(2024, time.April, 10, 0, 0, 0, 0, time.UTC)
+
+	dir := formatLayout(layout, known)
+	if dir == "" || filepath.IsAbs(dir) || filepath.Clean(dir) != filepath.FromSlash(dir) || strings.HasPrefix(filepath.Clean(dir), "..") {
+		return fmt.Errorf("invalid data layout %q: %q is not a directory below the data directory", layout, dir)
+	}
+
+	// the week token can not be parsed by time.Parse
+	if !strings.Contains(layout, isoWeekToken) {
+		parsed, err := time.Parse(layout, dir)
+		if err != nil {
+			return fmt.Errorf("invalid data layout %q: %w", layout, err)
+		}
+		if formatLayout(layout, parsed) != dir {
+			return fmt.Errorf("invalid data layout %q: %s is formatted as %s", layout, known.Format("2006-01-02"), dir)
+		}
+	}
+
+	return nil
+}
+
+// entryFilePath returns the path of the file storing the entries of date (2006-01-02) in dataDir.
+func entryFilePath(dataDir, date string) (string, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q: %w", date, err)
+	}
+
+	return dayFilePath(dataDir, day), nil
+}
+
+// dayFilePath returns the path of the file storing the entries of day in dataDir.
+func dayFilePath(dataDir string, day time.Time) string {
+	return filepath.Join(dataDir, formatLayout(dataLayout(), day), day.Format("2006-01-02")+".json")
+}
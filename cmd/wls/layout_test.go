@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatLayout(t *testing.T) {
+	tests := []struct {
+		layout string
+		day    time.Time
+		want   string
+	}{
+		{"2006/01", time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC), "2024/12"},
+		{"2006/W02", time.Date(2024, time.April, 10, 0, 0, 0, 0, time.UTC), "2024/W15"},
+		// the ISO week 1 of 2025 starts on 2024-12-30
+		{"2006/W02", time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC), "2025/W01"},
+		// 2021-01-03 belongs to the last ISO week of 2020
+		{"2006/W02", time.Date(2021, time.January, 3, 0, 0, 0, 0, time.UTC), "2020/W53"},
+	}
+
+	for _, tt := range tests {
+		if got := formatLayout(tt.layout, tt.day); got != tt.want {
+			t.Errorf("formatLayout(%q, %s) = %q, want %q", tt.layout, tt.day.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
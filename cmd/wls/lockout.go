@@ -0,0 +1,118 @@
+package main
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// failuresSweepInterval is the minimum time between two evictions of expired failed attempts.
+const failuresSweepInterval = time.Minute
+
+// failedAttempts tracks the consecutive authentication failures of one IP.
+type failedAttempts struct {
+	mu          sync.Mutex
+	count       int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// expired reports whether the IP is not locked out and had no failure within window.
+// The caller must hold mu.
+func (a *failedAttempts) expired(now time.Time, window time.Duration) bool {
+	return !now.Before(a.lockedUntil) && now.Sub(a.lastFailure) > window
+}
+
+// clientIP returns the IP of the remote address of the request.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// isLocked reports whether the IP is locked out after too many failed attempts.
+func (srv *Server) isLocked(ip string) bool {
+	v, ok := srv.failures.Load(ip)
+	if !ok {
+		return false
+	}
+
+	attempts := v.(*failedAttempts)
+	attempts.mu.Lock()
+	defer attempts.mu.Unlock()
+
+	return time.Now().Before(attempts.lockedUntil)
+}
+
+// authFailed counts a failed attempt of the IP and locks it out after
+// wls.auth.maxFailedAttempts consecutive failures for wls.auth.lockoutDuration.
+// Failures older than the lockout duration are forgotten.
+func (srv *Server) authFailed(ip string) {
+	now := time.Now()
+	srv.evictExpiredFailures(now)
+
+	v, _ := srv.failures.LoadOrStore(ip, &failedAttempts{})
+
+	attempts := v.(*failedAttempts)
+	attempts.mu.Lock()
+	defer attempts.mu.Unlock()
+
+	if attempts.expired(now, viper.GetDuration("wls.auth.lockoutDuration")) {
+		attempts.count = 0
+	}
+	attempts.lastFailure = now
+	attempts.count++
+	if attempts.count >= viper.GetInt("wls.auth.maxFailedAttempts") {
+		attempts.count = 0
+		attempts.lockedUntil = time.Now().Add(viper.GetDuration("wls.auth.lockoutDuration"))
+		slog.Warn("locked out after failed attempts", "ip", ip, "until", attempts.lockedUntil)
+	}
+}
+
+// evictExpiredFailures removes the IPs which are not locked out and had no failure within
+// wls.auth.lockoutDuration, so failed attempts of many clients do not pile up.
+// The failures are swept at most once per failuresSweepInterval.
+func (srv *Server) evictExpiredFailures(now time.Time) {
+	last := srv.failuresSwept.Load()
+	if now.Sub(time.Unix(0, last)) < failuresSweepInterval || !srv.failuresSwept.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+
+	window := viper.GetDuration("wls.auth.lockoutDuration")
+	srv.failures.Range(func(ip, v any) bool {
+		attempts := v.(*failedAttempts)
+		attempts.mu.Lock()
+		defer attempts.mu.Unlock()
+
+		if attempts.expired(now, window) {
+			srv.failures.CompareAndDelete(ip, v)
+		}
+		return true
+	})
+}
+
+// authSucceeded resets the failed attempts of the IP.
+func (srv *Server) authSucceeded(ip string) {
+	srv.failures.Delete(ip)
+}
+
+// unlock clears the lockout of the IP given by the ip parameter.
+func (srv *Server) unlock(w http.ResponseWriter, r *http.Request) {
+	ip := r.URL.Query().Get("ip")
+	if ip == "" {
+		writeError(w, http.StatusBadRequest, "ip parameter is required")
+		return
+	}
+
+	srv.failures.Delete(ip)
+	slog.Info("unlocked", "ip", ip)
+
+	writeError(w, http.StatusOK, "Unlocked "+ip)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// countFailures returns the number of IPs with failed attempts.
+func countFailures(srv *Server) int {
+	n := 0
+	srv.failures.Range(func(ip, v any) bool {
+		n++
+		return true
+	})
+
+	return n
+}
+
+func TestEvictExpiredFailures(t *testing.T) {
+	// the lockout duration is 5 minutes by default
+	srv := newTestServer(t)
+
+	now := time.Now()
+	for i := range 100 {
+		srv.authFailed("10.0.0." + strconv.Itoa(i))
+	}
+	// locked out for an hour after the last failure long ago
+	srv.failures.Store("10.0.1.1", &failedAttempts{
+		lastFailure: now.Add(-time.Hour),
+		lockedUntil: now.Add(time.Hour),
+	})
+
+	srv.evictExpiredFailures(now.Add(4 * time.Minute))
+	if n := countFailures(srv); n != 101 {
+		t.Fatalf("%d IPs after 4 minutes, want all 101", n)
+	}
+
+	srv.evictExpiredFailures(now.Add(6 * time.Minute))
+	if n := countFailures(srv); n != 1 {
+		t.Fatalf("%d IPs after 6 minutes, want only the locked one", n)
+	}
+	if !srv.isLocked("10.0.1.1") {
+		t.Error("the locked out IP was evicted")
+	}
+}
+
+func TestAuthFailedForgetsOldFailures(t *testing.T) {
+	srv := newTestServer(t)
+
+	// 4 failures long ago do not count towards the lockout of 5 failures
+	srv.failures.Store("10.0.0.1", &failedAttempts{count: 4, lastFailure: time.Now().Add(-time.Hour)})
+	srv.authFailed("10.0.0.1")
+	if srv.isLocked("10.0.0.1") {
+		t.Error("locked out by failures older than the lockout duration")
+	}
+}
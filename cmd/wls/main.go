@@ -1,19 +1,25 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"html"
 	"io"
-	"log"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"regexp"
-	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/b1tray3r/go/internal/redmine"
@@ -31,23 +37,66 @@ func hashPassword(password string) (string, error) {
 	return string(bytes), err
 }
 
+// passwordSecret returns the bcrypt hash of the configured password. A password which is
+// already a bcrypt hash, e.g. written by POST /password/change, is returned as it is.
+func passwordSecret(password string) (string, error) {
+	if _, err := bcrypt.Cost([]byte(password)); err == nil {
+		return password, nil
+	}
+
+	return hashPassword(password)
+}
+
 func NewServer(auth *BasicAuth) (*Server, error) {
-	secret, err := hashPassword(auth.Secret)
+	secret, err := passwordSecret(auth.Secret)
 	if err != nil {
 		return nil, err
 	}
 	auth.Secret = secret
 
+	pattern, err := compileEntryPattern(viper.GetViper())
+	if err != nil {
+		return nil, err
+	}
+
 	return &Server{
-		Auth: auth,
+		Auth:    auth,
+		pattern: pattern,
 	}, nil
 }
 
 type Server struct {
 	Auth *BasicAuth
 
+	// authMu guards Auth which can be changed at runtime, see changePassword and Reload.
+	authMu sync.RWMutex
+
+	// failures holds the *failedAttempts per client IP, failuresSwept the time of the
+	// last eviction of expired ones in unix nanoseconds, see evictExpiredFailures.
+	failures      sync.Map
+	failuresSwept atomic.Int64
+
+	// patternMu guards pattern, the compiled wls.parse.entryRegex which is replaced on reload.
+	patternMu sync.RWMutex
+	pattern   *regexp.Regexp
+
+	// clientMu guards client which is created on first use and replaced on reload, see redmineClient.
+	clientMu sync.Mutex
+	client   *redmine.Client
+
+	openIssuesCache openIssuesCache
+
+	// idempotency holds the responses of POST /log by Idempotency-Key.
+	idempotency idempotencyCache
+
 	init sync.Once
 	mux  *http.ServeMux
+
+	// done is closed by Shutdown to stop the background goroutines tracked by wg, see doneChan.
+	done     chan struct{}
+	doneInit sync.Once
+	shutdown sync.Once
+	wg       sync.WaitGroup
 }
 
 // HTTPMiddleware defines the required function interface which
@@ -55,16 +104,12 @@ type Server struct {
 type HTTPMiddleware func(http.HandlerFunc) http.HandlerFunc
 
 // withMiddleware will wrap the given handler with the
-// provided middleware(s) in reverse order.
+// provided middleware(s) in reverse order, so the first middleware runs first.
+// The slice of middlewares is not modified, it may be shared between handlers.
 func withMiddleware(h http.HandlerFunc, m ...HTTPMiddleware) http.HandlerFunc {
-	if len(m) < 1 {
-		return h
-	}
-	slices.Reverse(m)
-
 	wrapped := h
-	for _, mw := range m {
-		wrapped = mw(wrapped)
+	for i := len(m) - 1; i >= 0; i-- {
+		wrapped = m[i](wrapped)
 	}
 
 	return wrapped
@@ -74,21 +119,31 @@ func withMiddleware(h http.HandlerFunc, m ...HTTPMiddleware) http.HandlerFunc {
 func (srv *Server) withAuth(next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		slog.Info("with auth triggered")
+		ip := clientIP(r)
+		if srv.isLocked(ip) {
+			http.Error(w, "Too many failed attempts", http.StatusTooManyRequests)
+			slog.Error("rejected locked client", "ip", ip)
+			return
+		}
+
 		username, password, ok := r.BasicAuth()
 
 		if ok {
-			if username == srv.Auth.Username {
-				slog.Info("matching users", username, srv.Auth.Username)
-				if err := bcrypt.CompareHashAndPassword([]byte(srv.Auth.Secret), []byte(password)); err != nil {
+			if username == srv.username() {
+				slog.Info("matching users", username, username)
+				if err := srv.checkPassword(password); err != nil {
+					srv.authFailed(ip)
 					w.Header().Set("WWW-Authenticate", `Basic realm="restricted", charset="UTF-8"`)
 					http.Error(w, "Unauthorized", http.StatusUnauthorized)
 					slog.Error("failed to authenticate", "user", username, slog.Any("Error", err))
 					return
 				}
 
+				srv.authSucceeded(ip)
 				next.ServeHTTP(w, r)
 				return
 			}
+			srv.authFailed(ip)
 		}
 
 		w.Header().Set("WWW-Authenticate", `Basic realm="restricted", charset="UTF-8"`)
@@ -96,22 +151,108 @@ func (srv *Server) withAuth(next http.HandlerFunc) http.HandlerFunc {
 	})
 }
 
+// username returns the current username.
+func (srv *Server) username() string {
+	srv.authMu.RLock()
+	defer srv.authMu.RUnlock()
+
+	return srv.Auth.Username
+}
+
+// checkPassword compares the password with the current secret.
+func (srv *Server) checkPassword(password string) error {
+	srv.authMu.RLock()
+	defer srv.authMu.RUnlock()
+
+	return bcrypt.CompareHashAndPassword([]byte(srv.Auth.Secret), []byte(password))
+}
+
+// route is an endpoint of the API. An empty method matches all methods.
+type route struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
+}
+
+// pattern returns the ServeMux pattern of the route below prefix.
+func (rt route) pattern(prefix string) string {
+	if rt.method == "" {
+		return prefix + rt.path
+	}
+
+	return rt.method + " " + prefix + rt.path
+}
+
+// registerV1Routes registers all endpoints below /v1/ and returns them.
+func (srv *Server) registerV1Routes(router *http.ServeMux) []route {
+	routes := []route{
+		// public endpoints
+		{"", "/health", srv.healthCheck},
+
+		// private endpoints with auth
+		{http.MethodGet, "/all", withMiddleware(srv.listAll, srv.withAuth)},
+		{http.MethodGet, "/day", withMiddleware(srv.listEntriesforDay, srv.withAuth)},
+		{http.MethodGet, "/week", withMiddleware(srv.week, srv.withAuth)},
+		{http.MethodGet, "/issues/open", withMiddleware(srv.openIssues, srv.withAuth)},
+		{http.MethodGet, "/stats/tags", withMiddleware(srv.tagStats, srv.withAuth)},
+		{http.MethodGet, "/calendar", withMiddleware(srv.calendar, srv.withAuth)},
+		{http.MethodGet, "/report/monthly", withMiddleware(srv.monthlyReport, srv.withAuth)},
+
+		{http.MethodPost, "/sync", withMiddleware(srv.syncEntry, srv.withAuth)},
+		{http.MethodPost, "/log", withMiddleware(srv.handleAddLog, srv.withAuth, srv.withIdempotency)},
+		{http.MethodPost, "/log/bulk", withMiddleware(srv.handleBulkLog, srv.withAuth, srv.withIdempotency)},
+		{http.MethodPost, "/preview", withMiddleware(srv.preview, srv.withAuth)},
+		{http.MethodPost, "/password/change", withMiddleware(srv.changePassword, srv.withAuth)},
+		{http.MethodPost, "/admin/unlock", withMiddleware(srv.unlock, srv.withAuth)},
+	}
+
+	for _, rt := range routes {
+		router.HandleFunc(rt.pattern("/v1"), rt.handler)
+	}
+
+	return routes
+}
+
+// withDeprecation is a middleware marking the response as deprecated in favour of successor.
+func withDeprecation(successor string) HTTPMiddleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+			next.ServeHTTP(w, r)
+		}
+	}
+}
+
 // ServeHTTP implements the http.Handler interface.
-// It initializes the server and the available endpoints.
+// It initializes the server and the available endpoints. The unversioned
+// endpoints are deprecated aliases of the wls.server.apiVersion endpoints.
 func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	srv.init.Do(func() {
 		router := http.NewServeMux()
 
-		// public endpoints
-		router.HandleFunc("/health", srv.healthCheck)
-
-		// private endpoints with auth
+		versions := map[string]func(*http.ServeMux) []route{
+			"v1": srv.registerV1Routes,
+		}
 
-		router.HandleFunc("GET /all", srv.listAll)
-		router.HandleFunc("GET /day", srv.listEntriesforDay)
+		canonical := viper.GetString("wls.server.apiVersion")
+		var routes []route
+		for version, register := range versions {
+			if version == canonical {
+				routes = register(router)
+			} else {
+				register(router)
+			}
+		}
+		if routes == nil {
+			slog.Error("unknown api version, falling back to v1", "version", canonical)
+			canonical = "v1"
+			routes = srv.registerV1Routes(http.NewServeMux())
+		}
 
-		router.HandleFunc("POST /sync", srv.syncEntry)
-		router.HandleFunc("POST /log", srv.handleAddLog)
+		for _, rt := range routes {
+			router.HandleFunc(rt.pattern(""), withMiddleware(rt.handler, withDeprecation("/"+canonical+rt.path)))
+		}
 
 		srv.mux = router
 	})
@@ -125,6 +266,16 @@ type ServerResponse struct {
 	Message string `json:"message"`
 }
 
+// writeError responds with the status and message as ServerResponse.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(&ServerResponse{
+		Status:  status,
+		Message: message,
+	})
+}
+
 func (srv *Server) healthCheck(w http.ResponseWriter, r *http.Request) {
 	slog.Debug("health check requested")
 	json.NewEncoder(w).Encode(
@@ -146,23 +297,88 @@ type TimeEntry struct {
 	Tags   []Tag
 	Note   string
 	Synced bool
+
+	// RemoteID and RemoteURL identify the redmine time entry after the sync.
+	RemoteID  int64  `json:",omitempty"`
+	RemoteURL string `json:",omitempty"`
+}
+
+// streamAllEntries writes all entries stored in dataDir as JSON array to w.
+// The entries are encoded one by one so only a single day is held in memory.
+func streamAllEntries(w io.Writer, dataDir string) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	first := true
+	err := filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// no entries have been logged yet
+			if path == dataDir && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		var entries []TimeEntry
+		if err := json.NewDecoder(file).Decode(&entries); err != nil {
+			return fmt.Errorf("failed to decode entries of %s: %w", path, err)
+		}
+
+		for _, entry := range entries {
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return err
 }
 
 func (srv *Server) listAll(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("list all triggered")
 
+	w.Header().Set("Content-Type", "application/json")
+	// the status is already sent, errors can only be logged
+	if err := streamAllEntries(w, viper.GetString("wls.app.dataDir")); err != nil {
+		slog.Error("Failed to stream entries", "error", err)
+	}
 }
 
 func (srv *Server) listEntriesforDay(w http.ResponseWriter, r *http.Request) {
 	slog.Debug("list logs triggered")
 	date := r.URL.Query().Get("date")
-	year := date[:4]
-	month := date[5:7]
-	dataDir := viper.GetString("wls.app.dataDir")
-	filePath := filepath.Join(dataDir, year, month, date+".json")
 	if date == "" {
 		http.Error(w, "Date parameter is required", http.StatusBadRequest)
 		return
 	}
+	filePath, err := entryFilePath(viper.GetString("wls.app.dataDir"), date)
+	if err != nil {
+		http.Error(w, "Date parameter must be a date like 2024-01-15", http.StatusBadRequest)
+		return
+	}
 
 	slog.Debug("Reading entries for date", "date", date)
 
@@ -268,6 +484,9 @@ func (srv *Server) listEntriesforDay(w http.ResponseWriter, r *http.Request) {
 		if entry.Synced {
 			syncIcon = "&#9989;" // ✅
 		}
+		if entry.RemoteURL != "" {
+			syncIcon = "<a href='" + html.EscapeString(entry.RemoteURL) + "' target='_blank'>" + syncIcon + "</a>"
+		}
 		w.Write([]byte("<tr><td>" + strconv.FormatFloat(entry.Hours, 'f', 2, 64) + "</td><td>" + strings.Join(tags, ", ") + "</td><td>" + entry.Note + "</td>"))
 		w.Write([]byte("<td>" + syncIcon + "</td>"))
 		if !entry.Synced {
@@ -280,7 +499,7 @@ func (srv *Server) listEntriesforDay(w http.ResponseWriter, r *http.Request) {
 		<script>
 			function syncEntry(index) {
 				const baseUrl = window.location.origin;
-				fetch(baseUrl + '/sync', {
+				fetch(baseUrl + '/v1/sync', {
 					method: 'POST',
 					headers: {
 						'Content-Type': 'application/json'
@@ -327,10 +546,14 @@ func (srv *Server) syncEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	year := req.Date[:4]
-	month := req.Date[5:7]
-	dataDir := viper.GetString("wls.app.dataDir")
-	filePath := filepath.Join(dataDir, year, month, req.Date+".json")
+	// the date is validated before it is used to build the file path
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		http.Error(w, "Failed to parse date", http.StatusBadRequest)
+		slog.Error("Failed to parse date", "date", req.Date, "error", err)
+		return
+	}
+	filePath := dayFilePath(viper.GetString("wls.app.dataDir"), date)
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -352,6 +575,12 @@ func (srv *Server) syncEntry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(entries) == 0 {
+		http.Error(w, "No entries for given date", http.StatusNotFound)
+		slog.Error("No entries for given date", "date", req.Date)
+		return
+	}
+
 	if req.Index < 0 || req.Index >= len(entries) {
 		http.Error(w, "Invalid entry index", http.StatusBadRequest)
 		slog.Error("Invalid entry index", "index", req.Index)
@@ -369,25 +598,13 @@ func (srv *Server) syncEntry(w http.ResponseWriter, r *http.Request) {
 
 	duration := time.Duration(entry.Hours * float64(time.Hour))
 
-	rc, err := redmine.NewClient(
-		viper.GetString("wls.redmine.url"),
-		viper.GetString("wls.redmine.key"),
-		"",
-		viper.GetBool("wls.redmine.dryrun"),
-	)
+	rc, err := srv.redmineClient()
 	if err != nil {
 		http.Error(w, "Failed to create Redmine client", http.StatusInternalServerError)
 		slog.Error("Failed to create Redmine client", "error", err)
 		return
 	}
 
-	date, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		log.Printf("failed to parse date string to time %s", req.Date)
-		http.Error(w, "Failed to parse date", http.StatusBadRequest)
-		return
-	}
-
 	issueID := findInTags(entry.Tags, "issue")
 	if issueID == "" {
 		http.Error(w, "No issue ID found in tags", http.StatusBadRequest)
@@ -409,37 +626,62 @@ func (srv *Server) syncEntry(w http.ResponseWriter, r *http.Request) {
 		slog.Error("Failed to parse issue ID", "issueID", issueID)
 		return
 	}
+	// the project of the issue is needed to look up the activity, issues of projects
+	// the API key can not read are logged in wls.redmine.defaultProjectID
+	var pid string
 	issue, err := rc.GetIssue(iid)
 	if err != nil {
-		http.Error(w, "Failed to get issue", http.StatusInternalServerError)
-		slog.Error("Failed to get issue", "issueID", issueID)
-		return
+		pid = viper.GetString("wls.redmine.defaultProjectID")
+		if pid == "" {
+			http.Error(w, "Failed to get issue", http.StatusInternalServerError)
+			slog.Error("Failed to get issue", "issueID", issueID, "error", err)
+			return
+		}
+		slog.Warn("Failed to get issue, using the default project", "issueID", issueID, "project", pid, "error", err)
+	} else {
+		pid = strconv.Itoa(int(issue.Project.ID))
+	}
+
+	// viper stores the keys of maps in lower case
+	alias, hasAlias := viper.GetStringMapString("wls.redmine.activityAliases")[strings.ToLower(aID)]
+	if hasAlias {
+		aID = alias
 	}
 
-	pid := strconv.Itoa(int(issue.Project.ID))
 	activityID, err := rc.GetActivityID(pid, aID)
 	if err != nil {
+		if !hasAlias {
+			slog.Warn("No activity alias configured and no activity matches", "action", aID, "project", pid)
+		}
 		http.Error(w, "Failed to get activity ID", http.StatusInternalServerError)
 		slog.Error("Failed to get activity ID", "activityID", aID)
 		return
 	}
 
 	te := redmine.TimeEntry{
-		IssueIDs:   []string{fmt.Sprintf("%d", issue.ID)},
+		IssueIDs:   []string{rc.Prefix + strconv.FormatInt(iid, 10)},
 		ActivityID: strconv.Itoa(int(activityID)),
 		Start:      date,
 		Duration:   duration.Hours(),
 		IsRedmine:  true,
 		Comment:    entry.Note,
+
+		CustomFields: viper.GetStringMapString("wls.redmine.customFields"),
 	}
 
-	if err := rc.Log(te); err != nil {
+	remoteID, err := rc.CreateTimeEntry(te)
+	if err != nil {
 		http.Error(w, "Failed to log time entry", http.StatusInternalServerError)
 		slog.Error("Failed to log time entry", "error", err)
 		return
 	}
 
 	entries[req.Index].Synced = true
+	// dry runs do not create an entry
+	if remoteID != 0 {
+		entries[req.Index].RemoteID = remoteID
+		entries[req.Index].RemoteURL = rc.TimeEntryURL(remoteID)
+	}
 
 	// Store the entry
 	file, err = os.Create(filePath)
@@ -462,139 +704,64 @@ func (srv *Server) syncEntry(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-// handleStockUpdate is responsible to handle the incoming stock updates.
-func (srv *Server) handleAddLog(w http.ResponseWriter, r *http.Request) {
-	slog.Debug("add log triggered")
-	//w.Header().Set("Content-Type", "application/json")
-
-	regex := `\s+▶.*`
+// readLogBody reads the markdown log of the request body up to wls.server.requestBodyLimit.
+// On failure the error response is written and false is returned.
+func readLogBody(w http.ResponseWriter, r *http.Request) (string, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, viper.GetInt64("wls.server.requestBodyLimit"))
 	body, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		slog.Error("Request body too large", "limit", tooLarge.Limit)
+		return "", false
+	}
 	if err != nil {
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		slog.Error("Failed to read request body", "error", err)
-		return
+		return "", false
 	}
 	defer r.Body.Close()
 
-	re := regexp.MustCompile(regex)
-	matches := re.FindAllString(string(body), -1)
-	entries := make([]TimeEntry, 0)
-	for _, match := range matches {
-		if match == "" {
-			continue
-		}
-
-		split := strings.Split(match, "|")
-		h := strings.TrimSpace(split[1])
-		hours, err := strconv.ParseFloat(strings.TrimSpace(h), 64)
-		if err != nil {
-			http.Error(w, "Failed to parse hours", http.StatusBadRequest)
-			slog.Error("Failed to parse hours", "error", err)
-			return
-		}
-		note := strings.TrimSpace(split[4])
-		ts := split[3]
-
-		tags := make([]Tag, 0)
-		for _, t := range strings.Split(ts, " ") {
-			t = strings.TrimPrefix(t, "#")
-			if t == "" {
-				continue
-			}
-			p := strings.Split(t, "/")
-
-			tags = append(tags, Tag{
-				Name:  p[0],
-				Value: p[1],
-			})
-		}
-
-		id := strings.TrimSpace(split[2])
-
-		entries = append(entries, TimeEntry{
-			ID:    id,
-			Hours: hours,
-			Note:  note,
-			Tags:  tags,
-		})
-	}
-
-	// Extract the date from the markdown body
-	dateRegex := regexp.MustCompile(`#\s*(\d{4}-\d{2}-\d{2})`)
-	dateMatches := dateRegex.FindStringSubmatch(string(body))
-	if len(dateMatches) < 2 {
-		return
-	}
-	date := dateMatches[1]
+	return string(body), true
+}
 
-	// Create the data directory if it doesn't exist
-	year := date[:4]
-	month := date[5:7]
-	dataDir := viper.GetString("wls.app.dataDir")
-	if err := os.MkdirAll(filepath.Join(dataDir, year, month), os.ModePerm); err != nil {
-		http.Error(w, "Failed to create data directory", http.StatusInternalServerError)
-		slog.Error("Failed to create data directory", "error", err)
-		return
+// parseLogRequest reads the markdown log of the request body and parses its entries.
+// On failure the error response is written and false is returned.
+func (srv *Server) parseLogRequest(w http.ResponseWriter, r *http.Request) (string, []TimeEntry, bool) {
+	body, ok := readLogBody(w, r)
+	if !ok {
+		return "", nil, false
 	}
 
-	// Create the file with the date as the filename
-	filePath := filepath.Join(dataDir, year, month, date+".json")
-	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0644)
+	entries, err := parseEntries(srv.entryPattern(), body)
 	if err != nil {
-		http.Error(w, "Failed to create or open file", http.StatusInternalServerError)
-		slog.Error("Failed to create or open file", "error", err)
-		return
+		http.Error(w, "Failed to parse entries", http.StatusBadRequest)
+		slog.Error("Failed to parse entries", "error", err)
+		return "", nil, false
 	}
-	defer file.Close()
-
-	// Read the existing entries from the file
-	var existingEntries []TimeEntry
-	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&existingEntries); err != nil && err != io.EOF {
-		http.Error(w, "Failed to decode entries", http.StatusInternalServerError)
-		slog.Error("Failed to decode entries", "error", err)
-		return
-	}
-
-	updatedEntries := make([]TimeEntry, 0)
-	// Overwrite existing entries with new entries if the existing entry is not yet synced
-	for _, newEntry := range entries {
-		ID := newEntry.ID
-
-		for _, existingEntry := range existingEntries {
-			if existingEntry.ID != ID {
-				continue
-			}
 
-			if existingEntry.Synced {
-				newEntry.Synced = true
-				break
-			}
-		}
+	return body, entries, true
+}
 
-		updatedEntries = append(updatedEntries, newEntry)
-	}
+// handleStockUpdate is responsible to handle the incoming stock updates.
+func (srv *Server) handleAddLog(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("add log triggered")
+	//w.Header().Set("Content-Type", "application/json")
 
-	// Truncate the file before writing new entries
-	if err := file.Truncate(0); err != nil {
-		http.Error(w, "Failed to truncate file", http.StatusInternalServerError)
-		slog.Error("Failed to truncate file", "error", err)
+	body, entries, ok := srv.parseLogRequest(w, r)
+	if !ok {
 		return
 	}
 
-	// Move the file pointer to the beginning of the file
-	if _, err := file.Seek(0, 0); err != nil {
-		http.Error(w, "Failed to seek file", http.StatusInternalServerError)
-		slog.Error("Failed to seek file", "error", err)
+	date := parseDate(body)
+	if date == "" {
 		return
 	}
 
-	// Write the entries to the file as JSON
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(updatedEntries); err != nil {
-		http.Error(w, "Failed to write entries to file", http.StatusInternalServerError)
-		slog.Error("Failed to write entries to file", "error", err)
+	filePath, err := storeEntries(viper.GetString("wls.app.dataDir"), date, entries)
+	if err != nil {
+		http.Error(w, "Failed to store entries", http.StatusInternalServerError)
+		slog.Error("Failed to store entries", "error", err)
 		return
 	}
 
@@ -637,34 +804,103 @@ func setupLoglevel(verbosity int) {
 	slog.Debug("Log level is set to DEBUG.")
 }
 
-func setupConfig() {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yml")
-	viper.AddConfigPath(".")
+// setupConfig reads the configuration from configFile into the global viper instance.
+// If configFile is empty, config.yml is searched in the working directory and in ~/.config/wls.
+// Only an explicitly given file which can not be read is returned as error.
+func setupConfig(configFile string) error {
+	return readConfig(viper.GetViper(), configFile)
+}
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		slog.Error("Error getting home directory", "error", err)
-		return
+// readConfig reads the configuration from configFile into v and sets the defaults, see setupConfig.
+func readConfig(v *viper.Viper, configFile string) error {
+	if configFile != "" {
+		if _, err := os.Stat(configFile); err != nil {
+			return fmt.Errorf("config file %s: %w", configFile, err)
+		}
+		v.SetConfigFile(configFile)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yml")
+		v.AddConfigPath(".")
+
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			slog.Error("Error getting home directory", "error", err)
+			return nil
+		}
+		configPath := filepath.Join(homeDir, ".config", "wls")
+		v.AddConfigPath(configPath)
 	}
-	configPath := filepath.Join(homeDir, ".config", "wls")
-	viper.AddConfigPath(configPath)
 
 	// Read in environment variables that match
-	viper.AutomaticEnv()
+	v.AutomaticEnv()
 
 	// Read the config file
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
+		if configFile != "" {
+			return fmt.Errorf("error reading config file %s: %w", configFile, err)
+		}
 		slog.Error("Error reading config file", "error", err)
 	}
 
-	viper.SetDefault("wls.server.address", ":8085")
+	v.SetDefault("wls.server.address", ":8085")
+	v.SetDefault("wls.server.requestBodyLimit", 1<<20)
+	v.SetDefault("wls.server.apiVersion", "v1")
+	v.SetDefault("wls.calendar.startHour", 9)
+	v.SetDefault("wls.app.knownTags", []string{"issue", "action"})
+	v.SetDefault("wls.data.layout", "2006/01")
+	v.SetDefault("wls.auth.maxFailedAttempts", 5)
+	v.SetDefault("wls.auth.lockoutDuration", 5*time.Minute)
+
+	return nil
 }
 
 func main() {
-	setupConfig()
+	configFile := flag.String("config", "", "Path of the config file, config.yml is searched in ./ and ~/.config/wls/ otherwise")
+	flag.StringVar(configFile, "c", "", "Shorthand for --config")
+	configCheck := flag.Bool("config-check", false, "Check the configuration and the redmine connection without starting the server")
+	flag.Parse()
+
+	if err := setupConfig(*configFile); err != nil {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
 	setupLoglevel(viper.GetInt("wls.app.loglevel"))
 
+	if err := validateLayout(dataLayout()); err != nil && !*configCheck {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+	if _, err := compileEntryPattern(viper.GetViper()); err != nil && !*configCheck {
+		slog.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	if *configCheck {
+		if err := runConfigCheck(os.Stdout); err != nil {
+			slog.Error("config check failed", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// sub-commands which work on the data directory without a server
+	commands := map[string]func([]string) error{
+		"import":     runImport,
+		"export-csv": runExportCSV,
+		"validate":   runValidate,
+		"tui":        runTUI,
+	}
+	if args := flag.Args(); len(args) > 0 {
+		if run, ok := commands[args[0]]; ok {
+			if err := run(args[1:]); err != nil {
+				slog.Error(args[0]+" failed", "error", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	if viper.GetBool("wls.redmine.dryrun") {
 		slog.Warn("=== REDMINE: Dry run mode is enabled")
 	}
@@ -680,12 +916,41 @@ func main() {
 		os.Exit(1)
 	}
 
+	srv.reloadOnSignal()
+
 	addr := viper.GetString("wls.server.address")
+	httpServer := &http.Server{
+		Addr:    addr,
+		Handler: srv,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		<-ctx.Done()
+		slog.Info("Stopping server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			slog.Error("failed to stop server", "error", err)
+		}
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			slog.Error("failed to stop background tasks", "error", err)
+		}
+	}()
 
 	slog.Info("Starting server", "address", addr)
 	slog.Debug("With basic auth", "username", viper.GetString("wls.auth.username"), "secret", viper.GetString("wls.auth.password"))
-	if err := http.ListenAndServe(addr, srv); err != nil {
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		slog.Error("failed to start server", "error", err)
 		os.Exit(1)
 	}
+
+	// ListenAndServe returns immediately on Shutdown, wait for the background tasks
+	<-stopped
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestServer reads a config file with a temporary data directory into the global
+// viper instance and returns a server with the credentials admin/admin.
+func newTestServer(t testing.TB) *Server {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	config := "wls:\n  app:\n    dataDir: " + filepath.Join(dir, "data") + "\n"
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := setupConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(&BasicAuth{Username: "admin", Secret: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return srv
+}
+
+func TestRoutesRequireAuth(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/v1/all"},
+		{http.MethodGet, "/v1/day?date=2024-01-15"},
+		{http.MethodPost, "/v1/log"},
+		{http.MethodPost, "/v1/sync"},
+		{http.MethodGet, "/day?date=2024-01-15"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without credentials: status = %d, want %d", tt.method, tt.path, rec.Code, http.StatusUnauthorized)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/all", nil)
+	req.SetBasicAuth("admin", "admin")
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /v1/all with credentials: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("GET /v1/health: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+// recordingMiddleware appends name to order before calling the next handler.
+func recordingMiddleware(name int, order *[]int) HTTPMiddleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			*order = append(*order, name)
+			next(w, r)
+		}
+	}
+}
+
+func TestWithMiddlewareSingle(t *testing.T) {
+	var order []int
+	handlerCalled := false
+	h := withMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+	}, recordingMiddleware(1, &order))
+
+	h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !slices.Equal(order, []int{1}) || !handlerCalled {
+		t.Errorf("order = %v, handler called = %t, want the middleware before the handler", order, handlerCalled)
+	}
+}
+
+func TestWithMiddlewareOrder(t *testing.T) {
+	property := func(n uint8) bool {
+		var order []int
+		m := make([]HTTPMiddleware, n)
+		for i := range m {
+			m[i] = recordingMiddleware(i, &order)
+		}
+
+		h := withMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			order = append(order, -1)
+		}, m...)
+		h(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		// the middlewares run left to right before the handler
+		want := make([]int, 0, len(m)+1)
+		for i := range m {
+			want = append(want, i)
+		}
+		want = append(want, -1)
+
+		return slices.Equal(order, want)
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// entryRegex matches the time entry lines of a markdown log, a ▶ preceded by whitespace
+// like the indentation or the line break, up to the end of the line.
+var entryRegex = regexp.MustCompile(`\s+▶.*`)
+
+// entryLineRegex splits a time entry line into its fields. The expected format is
+//
+//	▶ | <hours> | <id> | <tags> | <note> |
+//
+// The tags are separated by spaces like #issue/123 #action/dev. The note is the
+// rest of the line and may contain pipes, only a trailing pipe is removed.
+// Pipes and newlines in the note can be encoded as %7C and %0A.
+var entryLineRegex = regexp.MustCompile(`^▶\s*\|\s*(?P<hours>[^|]*?)\s*\|\s*(?P<id>[^|]*?)\s*\|\s*(?P<tags>[^|]*?)\s*(?:\|\s*(?P<note>.*?))?\s*\|?\s*$`)
+
+// dateRegex matches the date heading of a markdown log at the start of a line,
+// tags like #2024-01-15 within a line are no heading.
+var dateRegex = regexp.MustCompile(`(?m)^#\s*(\d{4}-\d{2}-\d{2})`)
+
+// noteDecoder decodes the percent-encoded characters of a note.
+var noteDecoder = strings.NewReplacer("%7C", "|", "%7c", "|", "%0A", "\n", "%0a", "\n")
+
+// entryPatternGroups are the named groups required in wls.parse.entryRegex, the group id is optional.
+var entryPatternGroups = []string{"hours", "tags", "note"}
+
+// compileEntryPattern returns the compiled wls.parse.entryRegex of v or nil if the built-in ▶ format is used.
+// A custom pattern is matched against every line of the log, lines which do not match are ignored.
+// It is compiled once on startup and on reload, see Server.entryPattern.
+func compileEntryPattern(v *viper.Viper) (*regexp.Regexp, error) {
+	expr := v.GetString("wls.parse.entryRegex")
+	if expr == "" {
+		return nil, nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wls.parse.entryRegex: %w", err)
+	}
+	for _, name := range entryPatternGroups {
+		if re.SubexpIndex(name) < 0 {
+			return nil, fmt.Errorf("invalid wls.parse.entryRegex: named group %q is missing, e.g. (?P<%s>...)", name, name)
+		}
+	}
+
+	return re, nil
+}
+
+// entryPattern returns the entry pattern compiled on startup or on the last reload.
+func (srv *Server) entryPattern() *regexp.Regexp {
+	srv.patternMu.RLock()
+	defer srv.patternMu.RUnlock()
+
+	return srv.pattern
+}
+
+// parseEntries returns the time entries of the markdown log body.
+// Entry lines are matched by pattern, see compileEntryPattern, or by the built-in ▶ format if it is nil.
+func parseEntries(pattern *regexp.Regexp, body string) ([]TimeEntry, error) {
+	if pattern != nil {
+		return parseCustomEntries(pattern, body)
+	}
+
+	matches := entryRegex.FindAllString(body, -1)
+	entries := make([]TimeEntry, 0)
+	for _, match := range matches {
+		line := strings.TrimSpace(match)
+		if line == "" {
+			continue
+		}
+
+		fields := entryLineRegex.FindStringSubmatch(line)
+		if fields == nil {
+			return nil, fmt.Errorf("invalid entry line %q", line)
+		}
+		field := func(name string) string {
+			return fields[entryLineRegex.SubexpIndex(name)]
+		}
+
+		entry, err := newEntry(field("id"), field("hours"), field("tags"), field("note"))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// parseCustomEntries returns the time entries of all lines of body matching pattern, see compileEntryPattern.
+func parseCustomEntries(pattern *regexp.Regexp, body string) ([]TimeEntry, error) {
+	entries := make([]TimeEntry, 0)
+	for _, line := range strings.Split(body, "\n") {
+		fields := pattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if fields == nil {
+			continue
+		}
+		field := func(name string) string {
+			if i := pattern.SubexpIndex(name); i >= 0 {
+				return strings.TrimSpace(fields[i])
+			}
+			return ""
+		}
+
+		entry, err := newEntry(field("id"), field("hours"), field("tags"), field("note"))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// newEntry creates a time entry from the fields of an entry line.
+func newEntry(id, hours, tags, note string) (TimeEntry, error) {
+	h, err := strconv.ParseFloat(hours, 64)
+	if err != nil {
+		return TimeEntry{}, fmt.Errorf("failed to parse hours: %w", err)
+	}
+
+	entryTags := make([]Tag, 0)
+	for _, t := range strings.Fields(tags) {
+		t = strings.TrimPrefix(t, "#")
+		if t == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(t, "/")
+
+		entryTags = append(entryTags, Tag{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	return TimeEntry{
+		ID:    id,
+		Hours: h,
+		Note:  noteDecoder.Replace(note),
+		Tags:  entryTags,
+	}, nil
+}
+
+// parseDate returns the date of the markdown log body or an empty string if it has none.
+func parseDate(body string) string {
+	matches := dateRegex.FindStringSubmatch(body)
+	if len(matches) < 2 {
+		return ""
+	}
+
+	return matches[1]
+}
+
+// splitDays splits a markdown log with multiple date headings into the blocks of each date.
+// Text before the first heading is ignored, blocks of the same date are joined.
+// The dates are returned in the order of their first heading.
+func splitDays(body string) ([]string, map[string]string) {
+	matches := dateRegex.FindAllStringSubmatchIndex(body, -1)
+
+	dates := make([]string, 0, len(matches))
+	blocks := make(map[string]string, len(matches))
+	for i, match := range matches {
+		end := len(body)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+
+		date := body[match[2]:match[3]]
+		if _, ok := blocks[date]; !ok {
+			dates = append(dates, date)
+		}
+		blocks[date] += body[match[0]:end]
+	}
+
+	return dates, blocks
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzParseMarkdownEntries(f *testing.F) {
+	seeds := []string{
+		"# 2024-01-15\n\n  ▶ | 1.5 | a1 | #issue/123 #action/dev | fixed the login |\n",
+		"# 2024-01-15\n  ▶ | 2 | a2 | #issue/1 | note with | pipe\n  ▶ | 0.25 | a3 | #action/review |",
+		"  ▶ | 1 | a4 | #issue/7 | encoded %7C pipe%0Aand newline |",
+		"  ▶ | 1 | a5 | #issue/7",
+		"  ▶ | 1 |",
+		"  ▶ |",
+		"  ▶",
+		" ▶▶▶ ||||",
+		"  ▶ | x | a6 | #issue/7 | hours are no number |",
+		"  ▶ | 1 | a7 | # #/ / | empty tags |",
+		"",
+		"# 2024-01-15\nno entries at all",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, body string) {
+		entries, err := parseEntries(nil, body)
+		if err != nil {
+			return
+		}
+
+		// every entry is parsed from its own line starting with ▶
+		if n := strings.Count(body, "▶"); len(entries) > n {
+			t.Errorf("parsed %d entries from %d entry markers", len(entries), n)
+		}
+	})
+}
+
+func TestParseEntriesNote(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		note string
+	}{
+		{"empty", "  ▶ | 1 | a1 | #issue/1 |  |", ""},
+		{"missing", "  ▶ | 1 | a1 | #issue/1", ""},
+		{"missing with pipe", "  ▶ | 1 | a1 | #issue/1 |", ""},
+		{"plain", "  ▶ | 1 | a1 | #issue/1 | fixed the login |", "fixed the login"},
+		{"without trailing pipe", "  ▶ | 1 | a1 | #issue/1 | fixed the login", "fixed the login"},
+		{"pipes", "  ▶ | 1 | a1 | #issue/1 | a | b | c |", "a | b | c"},
+		{"encoded pipe", "  ▶ | 1 | a1 | #issue/1 | a %7C b %7c c |", "a | b | c"},
+		{"encoded newline", "  ▶ | 1 | a1 | #issue/1 | first%0Asecond%0athird |", "first\nsecond\nthird"},
+		{"unicode", "  ▶ | 1 | a1 | #issue/1 | Überprüfung der Größe 日本語 ✓ |", "Überprüfung der Größe 日本語 ✓"},
+		{"percent", "  ▶ | 1 | a1 | #issue/1 | 100% done %7 |", "100% done %7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := parseEntries(nil, "# 2024-01-15\n"+tt.line+"\n")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			if entries[0].Note != tt.note {
+				t.Errorf("note = %q, want %q", entries[0].Note, tt.note)
+			}
+		})
+	}
+}
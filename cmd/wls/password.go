@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// changePassword replaces the password of the server after verifying the current one.
+// If wls.auth.persist is set, the bcrypt hash of the new password is written to the config file.
+func (srv *Server) changePassword(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("password change triggered")
+
+	var req struct {
+		Current string `json:"current"`
+		New     string `json:"new"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Failed to decode request body")
+		slog.Error("Failed to decode request body", "error", err)
+		return
+	}
+	if req.New == "" {
+		writeError(w, http.StatusBadRequest, "New password must not be empty")
+		return
+	}
+
+	if err := srv.checkPassword(req.Current); err != nil {
+		srv.authFailed(clientIP(r))
+		writeError(w, http.StatusForbidden, "Current password does not match")
+		slog.Error("failed to change password", "error", err)
+		return
+	}
+
+	secret, err := hashPassword(req.New)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to hash password")
+		slog.Error("Failed to hash password", "error", err)
+		return
+	}
+
+	// the password is persisted first, so a failure leaves the current password in place
+	srv.authMu.Lock()
+	defer srv.authMu.Unlock()
+
+	if viper.GetBool("wls.auth.persist") {
+		if err := persistPassword(viper.ConfigFileUsed(), secret); err != nil {
+			writeError(w, http.StatusInternalServerError, "Failed to write config file, password not changed")
+			slog.Error("Failed to write config file", "error", err)
+			return
+		}
+	}
+
+	srv.Auth.Secret = secret
+
+	slog.Info("Password changed")
+	json.NewEncoder(w).Encode(&ServerResponse{
+		Status:  http.StatusOK,
+		Message: "Password changed",
+	})
+}
+
+// persistPassword stores secret as wls.auth.password in the config file at path.
+// Only this key is changed, the other settings and comments of the file are kept.
+func persistPassword(path, secret string) error {
+	if path == "" {
+		return fmt.Errorf("no config file in use")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	auth := mappingValue(mappingValue(doc.Content[0], "wls"), "auth")
+	password := mappingValue(auth, "password")
+	password.Kind, password.Tag, password.Value, password.Style = yaml.ScalarNode, "!!str", secret, yaml.DoubleQuotedStyle
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// mappingValue returns the value of key in the mapping node, a missing key is added as empty mapping.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	value := &yaml.Node{Kind: yaml.MappingNode}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+
+	return value
+}
+
+// writeFileAtomic replaces the file at path with content keeping its permissions.
+func writeFileAtomic(path string, content []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// removing fails once the file is renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+const persistConfig = `wls:
+  auth:
+    username: admin
+    password: admin
+    persist: true
+  redmine:
+    # the api key must stay as it is
+    key: secret-key
+`
+
+func changePasswordRequest(t *testing.T, srv *Server, current, new string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body := `{"current":"` + current + `","new":"` + new + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/password/change", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	srv.changePassword(rec, req)
+
+	return rec
+}
+
+func TestChangePasswordPersistsHash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(persistConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := setupConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(&BasicAuth{Username: "admin", Secret: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := changePasswordRequest(t, srv, "admin", "n3w-pass"); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := string(content)
+	if strings.Contains(config, "n3w-pass") {
+		t.Error("config file contains the plain text password")
+	}
+	if !strings.Contains(config, "key: secret-key") || !strings.Contains(config, "# the api key must stay as it is") {
+		t.Errorf("other settings were changed:\n%s", config)
+	}
+	if strings.Contains(config, "requestBodyLimit") {
+		t.Errorf("defaults were written:\n%s", config)
+	}
+
+	// a restart reads the hash from the config file
+	if err := setupConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	restarted, err := NewServer(&BasicAuth{Username: "admin", Secret: viper.GetString("wls.auth.password")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restarted.checkPassword("n3w-pass"); err != nil {
+		t.Errorf("persisted password rejected after restart: %v", err)
+	}
+}
+
+func TestChangePasswordKeepsPasswordIfPersistFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	if err := os.WriteFile(path, []byte(persistConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := setupConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(&BasicAuth{Username: "admin", Secret: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := changePasswordRequest(t, srv, "admin", "n3w-pass"); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if err := srv.checkPassword("admin"); err != nil {
+		t.Errorf("current password rejected after failed change: %v", err)
+	}
+}
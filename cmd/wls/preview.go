@@ -0,0 +1,21 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// preview parses the markdown log like POST /log and returns the entries
+// without storing them, e.g. to review them before saving.
+func (srv *Server) preview(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("preview triggered")
+
+	_, entries, ok := srv.parseLogRequest(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/viper"
+)
+
+// Reload replaces the credentials of the server. The password of auth is hashed, see passwordSecret,
+// before it is stored, requests being authenticated during the swap use the old credentials.
+func (srv *Server) Reload(auth *BasicAuth) error {
+	if auth.Username == "" || auth.Secret == "" {
+		return fmt.Errorf("username and password must not be empty")
+	}
+
+	secret, err := passwordSecret(auth.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	srv.authMu.Lock()
+	srv.Auth = &BasicAuth{
+		Username: auth.Username,
+		Secret:   secret,
+	}
+	srv.authMu.Unlock()
+
+	return nil
+}
+
+// reloadConfig applies the credentials, the entry pattern and the redmine client of config.
+// Nothing is changed if the credentials or the entry pattern are invalid, the current
+// redmine client is kept if config has no valid redmine settings.
+func (srv *Server) reloadConfig(config *viper.Viper) error {
+	pattern, err := compileEntryPattern(config)
+	if err != nil {
+		return err
+	}
+
+	err = srv.Reload(&BasicAuth{
+		Username: config.GetString("wls.auth.username"),
+		Secret:   config.GetString("wls.auth.password"),
+	})
+	if err != nil {
+		return err
+	}
+
+	srv.patternMu.Lock()
+	srv.pattern = pattern
+	srv.patternMu.Unlock()
+
+	if err := srv.reloadClient(config); err != nil {
+		slog.Warn("Redmine client not reloaded", "error", err)
+	}
+
+	return nil
+}
+
+// reloadOnSignal reads the configuration file again and reloads it, see reloadConfig,
+// whenever the process receives SIGHUP. A failed reload keeps the current configuration.
+// Reloading stops on Shutdown.
+func (srv *Server) reloadOnSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	srv.background(func(done <-chan struct{}) {
+		defer signal.Stop(signals)
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-signals:
+			}
+
+			slog.Info("Reloading configuration")
+			// the file read at startup is read again, also if it was found by the search.
+			// It is read into a new instance, the global one is read by the handlers concurrently.
+			config := viper.New()
+			if err := readConfig(config, viper.ConfigFileUsed()); err != nil {
+				slog.Info("Configuration not reloaded", "error", err)
+				continue
+			}
+
+			if err := srv.reloadConfig(config); err != nil {
+				slog.Info("Configuration not reloaded", "error", err)
+				continue
+			}
+			slog.Info("Configuration reloaded")
+		}
+	})
+}
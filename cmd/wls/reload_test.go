@@ -0,0 +1,106 @@
+//go:build unix
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func writeConfig(t *testing.T, path, username, password string) {
+	t.Helper()
+
+	config := "wls:\n  auth:\n    username: " + username + "\n    password: " + password + "\n"
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	writeConfig(t, path, "admin", "admin")
+	if err := setupConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	srv, err := NewServer(&BasicAuth{Username: "admin", Secret: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Shutdown(context.Background())
+
+	srv.reloadOnSignal()
+
+	writeConfig(t, path, "sam", "secret")
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.username() != "sam" {
+		if time.Now().After(deadline) {
+			t.Fatalf("username = %q after SIGHUP, want %q", srv.username(), "sam")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := srv.checkPassword("secret"); err != nil {
+		t.Errorf("new password rejected: %v", err)
+	}
+}
+
+func TestReloadRejectsEmptyCredentials(t *testing.T) {
+	srv, err := NewServer(&BasicAuth{Username: "admin", Secret: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.Reload(&BasicAuth{Username: "sam"}); err == nil {
+		t.Error("expected error for empty password")
+	}
+	if srv.username() != "admin" {
+		t.Errorf("username = %q, want the old credentials", srv.username())
+	}
+}
+
+func TestReloadConfigEntryPattern(t *testing.T) {
+	srv, err := NewServer(&BasicAuth{Username: "admin", Secret: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := viper.New()
+	config.Set("wls.auth.username", "sam")
+	config.Set("wls.auth.password", "secret")
+	config.Set("wls.parse.entryRegex", `^- (?P<hours>[\d.]+)h (?P<tags>(?:#\S+\s+)*)(?P<note>.*)$`)
+	if err := srv.reloadConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := parseEntries(srv.entryPattern(), "# 2024-01-15\n- 1.5h #issue/1 fixed the login\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Hours != 1.5 || entries[0].Note != "fixed the login" {
+		t.Errorf("entries = %+v, want the entry of the reloaded pattern", entries)
+	}
+
+	// a pattern without the required groups keeps the whole configuration
+	config.Set("wls.auth.username", "alex")
+	config.Set("wls.parse.entryRegex", `^- (?P<hours>[\d.]+)h`)
+	if err := srv.reloadConfig(config); err == nil {
+		t.Fatal("reloadConfig accepted an entry pattern without tags and note")
+	}
+	if srv.username() != "sam" {
+		t.Errorf("username = %q, want the credentials of the last valid reload", srv.username())
+	}
+	if srv.entryPattern() == nil {
+		t.Error("invalid reload reset the entry pattern")
+	}
+}
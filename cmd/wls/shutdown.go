@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// shutdownTimeout is the maximum time to wait for open requests and background tasks on exit.
+const shutdownTimeout = 10 * time.Second
+
+// background runs fn in a goroutine which is awaited by Shutdown.
+// fn must return once done is closed.
+func (srv *Server) background(fn func(done <-chan struct{})) {
+	srv.wg.Add(1)
+	go func() {
+		defer srv.wg.Done()
+		fn(srv.doneChan())
+	}()
+}
+
+// doneChan returns the channel closed by Shutdown, it is created on first use
+// so servers which are not created by NewServer can be shut down as well.
+func (srv *Server) doneChan() chan struct{} {
+	srv.doneInit.Do(func() {
+		srv.done = make(chan struct{})
+	})
+
+	return srv.done
+}
+
+// Shutdown stops the background goroutines of the server and waits until they exited
+// or ctx is done. It does not close open connections, see http.Server.Shutdown.
+func (srv *Server) Shutdown(ctx context.Context) error {
+	srv.shutdown.Do(func() {
+		close(srv.doneChan())
+	})
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestShutdownStopsBackground(t *testing.T) {
+	srv, err := NewServer(&BasicAuth{Username: "admin", Secret: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := make(chan struct{})
+	srv.background(func(done <-chan struct{}) {
+		<-done
+		close(stopped)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-stopped:
+	default:
+		t.Error("Shutdown returned before the background task stopped")
+	}
+
+	// a second shutdown does not close the channel again
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShutdownWithoutNewServer(t *testing.T) {
+	srv := &Server{}
+	defer srv.Shutdown(context.Background())
+
+	srv.background(func(done <-chan struct{}) {
+		<-done
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// TagStats is the logged time of all entries with the same tag value.
+type TagStats struct {
+	Value      string  `json:"value"`
+	TotalHours float64 `json:"totalHours"`
+	EntryCount int     `json:"entryCount"`
+}
+
+// parseDateRange reads the from and to query parameters.
+func parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	from, err := time.Parse("2006-01-02", r.URL.Query().Get("from"))
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("from parameter must be a date like 2024-01-01")
+	}
+	to, err := time.Parse("2006-01-02", r.URL.Query().Get("to"))
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("to parameter must be a date like 2024-01-31")
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("to parameter must not be before from")
+	}
+
+	return from, to, nil
+}
+
+// tagStats sums the hours per value of the tag given by the name parameter,
+// sorted by the total hours descending.
+func (srv *Server) tagStats(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("tag stats triggered")
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "name parameter is required")
+		return
+	}
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats := make(map[string]*TagStats)
+	err = walkDateRange(viper.GetString("wls.app.dataDir"), from, to, func(date string, entries []TimeEntry) error {
+		for _, entry := range entries {
+			for _, tag := range entry.Tags {
+				if tag.Name != name {
+					continue
+				}
+				if stats[tag.Value] == nil {
+					stats[tag.Value] = &TagStats{Value: tag.Value}
+				}
+				stats[tag.Value].TotalHours += entry.Hours
+				stats[tag.Value].EntryCount++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to read entries")
+		slog.Error("Failed to read entries", "error", err)
+		return
+	}
+
+	result := make([]TagStats, 0, len(stats))
+	for _, s := range stats {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalHours == result[j].TotalHours {
+			return result[i].Value < result[j].Value
+		}
+		return result[i].TotalHours > result[j].TotalHours
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
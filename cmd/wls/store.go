@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// storeEntries replaces the entries of date in dataDir with entries and returns the path of the file.
+// Entries which were already synced keep their synced state.
+func storeEntries(dataDir, date string, entries []TimeEntry) (string, error) {
+	filePath, err := entryFilePath(dataDir, date)
+	if err != nil {
+		return "", err
+	}
+
+	// Create the data directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	// Create the file with the date as the filename
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create or open file: %w", err)
+	}
+	defer file.Close()
+
+	// Read the existing entries from the file
+	var existingEntries []TimeEntry
+	decoder := json.NewDecoder(file)
+	if err := decoder.Decode(&existingEntries); err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to decode entries: %w", err)
+	}
+
+	updatedEntries := make([]TimeEntry, 0)
+	// Overwrite existing entries with new entries if the existing entry is not yet synced
+	for _, newEntry := range entries {
+		ID := newEntry.ID
+
+		for _, existingEntry := range existingEntries {
+			if existingEntry.ID != ID {
+				continue
+			}
+
+			if existingEntry.Synced {
+				newEntry.Synced = true
+				break
+			}
+		}
+
+		updatedEntries = append(updatedEntries, newEntry)
+	}
+
+	// Truncate the file before writing new entries
+	if err := file.Truncate(0); err != nil {
+		return "", fmt.Errorf("failed to truncate file: %w", err)
+	}
+
+	// Move the file pointer to the beginning of the file
+	if _, err := file.Seek(0, 0); err != nil {
+		return "", fmt.Errorf("failed to seek file: %w", err)
+	}
+
+	// Write the entries to the file as JSON
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(updatedEntries); err != nil {
+		return "", fmt.Errorf("failed to write entries to file: %w", err)
+	}
+
+	return filePath, nil
+}
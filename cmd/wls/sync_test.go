@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// writeEntryFile writes the raw content of the entry file of date.
+func writeEntryFile(t testing.TB, date, content string) {
+	t.Helper()
+
+	path, err := entryFilePath(viper.GetString("wls.app.dataDir"), date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncEntryWithoutEntries(t *testing.T) {
+	srv := newTestServer(t)
+	writeEntryFile(t, "2024-01-15", "[]")
+
+	req := httptest.NewRequest(http.MethodPost, "/sync", strings.NewReader(`{"date":"2024-01-15","index":0}`))
+	rec := httptest.NewRecorder()
+	srv.syncEntry(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestSyncEntryIssuePrefix(t *testing.T) {
+	var issueID int64
+	redmine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/issues/123.json":
+			w.Write([]byte(`{"issue":{"id":123,"project":{"id":1}}}`))
+		case "/projects/1.json":
+			w.Write([]byte(`{"project":{"id":1,"time_entry_activities":[{"id":9,"name":"dev"}]}}`))
+		case "/time_entries.json":
+			var body struct {
+				TimeEntry struct {
+					IssueID int64 `json:"issue_id"`
+				} `json:"time_entry"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			issueID = body.TimeEntry.IssueID
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"time_entry":{"id":5}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer redmine.Close()
+
+	t.Setenv("REDMINE_URL", redmine.URL)
+	t.Setenv("REDMINE_API_KEY", "key")
+	t.Setenv("REDMINE_PREFIX", "RM-")
+	srv := newTestServer(t)
+	writeEntryFile(t, "2024-01-15", `[{"ID":"a1","Hours":1,"Tags":[{"Name":"issue","Value":"123"},{"Name":"action","Value":"dev"}]}]`)
+
+	req := httptest.NewRequest(http.MethodPost, "/sync", strings.NewReader(`{"date":"2024-01-15","index":0}`))
+	rec := httptest.NewRecorder()
+	srv.syncEntry(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	if issueID != 123 {
+		t.Errorf("logged issue %d, want 123", issueID)
+	}
+}
+
+func FuzzSyncEntry(f *testing.F) {
+	srv := newTestServer(f)
+	// synced entries are rejected before redmine is contacted
+	writeEntryFile(f, "2024-01-15", `[{"ID":"a1","Hours":1,"Synced":true}]`)
+
+	seeds := []string{
+		`{"date":"2024-01-15","index":0}`,
+		`{"date":"2024-01-15","index":1}`,
+		`{"date":"2024-01-15","index":-1}`,
+		`{"date":"2024-01-16","index":0}`,
+		`{"date":"../../etc/passwd","index":0}`,
+		`{"date":"2024-13-01","index":0}`,
+		`{"date":"","index":0}`,
+		`{}`,
+		`[]`,
+		``,
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, body string) {
+		req := httptest.NewRequest(http.MethodPost, "/sync", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		srv.syncEntry(rec, req)
+
+		if rec.Code != http.StatusBadRequest && rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d for %q, want 400 or 404", rec.Code, body)
+		}
+	})
+}
+
+func FuzzListEntriesforDay(f *testing.F) {
+	srv := newTestServer(f)
+	writeEntryFile(f, "2024-01-15", `[{"ID":"a1","Hours":1.5,"Note":"<b>fixed</b>"}]`)
+
+	seeds := []string{
+		"2024-01-15",
+		"2024-01-16",
+		"2024-02-30",
+		"../2024-01-15",
+		"2024-01-15/../../config",
+		"",
+		"<script>",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, date string) {
+		req := httptest.NewRequest(http.MethodGet, "/list?"+url.Values{"date": {date}}.Encode(), nil)
+		rec := httptest.NewRecorder()
+		srv.listEntriesforDay(rec, req)
+
+		switch rec.Code {
+		case http.StatusOK:
+			if date != "2024-01-15" {
+				t.Errorf("listed entries for %q", date)
+			}
+		case http.StatusBadRequest, http.StatusNotFound:
+		default:
+			t.Errorf("status = %d for %q, want 200, 400 or 404", rec.Code, date)
+		}
+	})
+}
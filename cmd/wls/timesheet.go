@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// timesheetHeader are the column names of the monthly timesheet.
+var timesheetHeader = []string{"Date", "Issue", "Activity", "Hours", "Note"}
+
+// formatTimesheet returns rows as text table with fixed-width columns and a totals row.
+// The total is summed from the rounded hours so it matches the printed rows.
+func formatTimesheet(rows [][]string, totalCents int64) string {
+	total := []string{"Total", "", "", fmt.Sprintf("%d.%02d", totalCents/100, totalCents%100), ""}
+
+	widths := make([]int, len(timesheetHeader))
+	for _, row := range append([][]string{timesheetHeader, total}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	line := func(row []string) string {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			// hours are right aligned
+			if i == 3 {
+				cells[i] = fmt.Sprintf("%*s", widths[i], cell)
+			} else {
+				cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+			}
+		}
+		return strings.TrimRight(strings.Join(cells, " | "), " ") + "\n"
+	}
+
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("-", width)
+	}
+	separator := strings.Join(separators, "-+-") + "\n"
+
+	var sb strings.Builder
+	sb.WriteString(line(timesheetHeader))
+	sb.WriteString(separator)
+	for _, row := range rows {
+		sb.WriteString(line(row))
+	}
+	sb.WriteString(separator)
+	sb.WriteString(line(total))
+
+	return sb.String()
+}
+
+// monthlyReport returns all entries of the month parameter as plain text timesheet.
+func (srv *Server) monthlyReport(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("monthly report triggered")
+
+	month := r.URL.Query().Get("month")
+	from, err := time.Parse("2006-01", month)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "month parameter must be a month like 2024-01")
+		return
+	}
+	to := from.AddDate(0, 1, -1)
+
+	var rows [][]string
+	var totalCents int64
+	err = walkDateRange(viper.GetString("wls.app.dataDir"), from, to, func(date string, entries []TimeEntry) error {
+		for _, entry := range entries {
+			cents := int64(math.Round(entry.Hours * 100))
+			totalCents += cents
+
+			rows = append(rows, []string{
+				date,
+				findInTags(entry.Tags, "issue"),
+				findInTags(entry.Tags, "action"),
+				fmt.Sprintf("%d.%02d", cents/100, cents%100),
+				entry.Note,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Failed to read entries")
+		slog.Error("Failed to read entries", "error", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="timesheet-%s.txt"`, month))
+	w.Write([]byte(formatTimesheet(rows, totalCents)))
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/viper"
+)
+
+// tuiModel is the terminal dashboard of the entries of a single day.
+// The entries are read from the data directory, syncing requires a running server.
+type tuiModel struct {
+	path    string
+	date    string
+	syncURL string
+
+	entries []TimeEntry
+	cursor  int
+	status  string
+}
+
+// syncDoneMsg reports the result of a sync request.
+type syncDoneMsg struct {
+	index int
+	err   error
+}
+
+// reload reads the entries of the day again. A missing file shows an empty day.
+func (m *tuiModel) reload() {
+	entries, err := readEntries(m.path)
+	switch {
+	case os.IsNotExist(err):
+		m.entries = nil
+		m.status = "no entries for " + m.date
+	case err != nil:
+		m.status = "failed to read entries: " + err.Error()
+	default:
+		m.entries = entries
+		m.status = ""
+	}
+	m.cursor = min(m.cursor, max(len(m.entries)-1, 0))
+}
+
+// syncEntry requests the server to sync the entry with the given index.
+func (m tuiModel) syncEntry(index int) tea.Cmd {
+	return func() tea.Msg {
+		body, err := json.Marshal(map[string]any{"date": m.date, "index": index})
+		if err != nil {
+			return syncDoneMsg{index, err}
+		}
+
+		client := http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Post(m.syncURL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return syncDoneMsg{index, err}
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return syncDoneMsg{index, fmt.Errorf("server responded with %s", resp.Status)}
+		}
+
+		return syncDoneMsg{index, nil}
+	}
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.entries)-1 {
+				m.cursor++
+			}
+		case "r":
+			m.reload()
+		case "s":
+			if m.cursor >= len(m.entries) {
+				break
+			}
+			if m.entries[m.cursor].Synced {
+				m.status = "entry is already synced"
+				break
+			}
+			m.status = "syncing entry " + strconv.Itoa(m.cursor+1) + "..."
+			return m, m.syncEntry(m.cursor)
+		}
+
+	case syncDoneMsg:
+		m.reload()
+		if msg.err != nil {
+			m.status = fmt.Sprintf("failed to sync entry %d: %v", msg.index+1, msg.err)
+		} else {
+			m.status = fmt.Sprintf("entry %d synced", msg.index+1)
+		}
+	}
+
+	return m, nil
+}
+
+func (m tuiModel) View() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "wls %s\n\n", m.date)
+	fmt.Fprintf(&sb, "  %6s  %-30s  %-40s  %s\n", "Hours", "Tags", "Note", "Synced")
+
+	var total float64
+	for i, entry := range m.entries {
+		total += entry.Hours
+
+		tags := make([]string, len(entry.Tags))
+		for j, tag := range entry.Tags {
+			tags[j] = tag.Name + "/" + tag.Value
+		}
+
+		synced := "no"
+		if entry.Synced {
+			synced = "yes"
+		}
+
+		cursor := " "
+		if i == m.cursor {
+			cursor = ">"
+		}
+		fmt.Fprintf(&sb, "%s %6.2f  %-30.30s  %-40.40s  %s\n", cursor, entry.Hours, strings.Join(tags, " "), entry.Note, synced)
+	}
+	fmt.Fprintf(&sb, "  %6.2f  total\n\n", total)
+
+	if m.status != "" {
+		sb.WriteString(m.status + "\n")
+	}
+	sb.WriteString("↑/↓ select • s sync • r refresh • q quit\n")
+
+	return sb.String()
+}
+
+// serverURL returns the base URL of the local server listening on wls.server.address.
+func serverURL() string {
+	host, port, err := net.SplitHostPort(viper.GetString("wls.server.address"))
+	if err != nil {
+		return "http://" + viper.GetString("wls.server.address")
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+// runTUI shows the entries of today in an interactive terminal dashboard.
+func runTUI(args []string) error {
+	fset := flag.NewFlagSet("tui", flag.ExitOnError)
+	dateFlag := fset.String("date", time.Now().Format("2006-01-02"), "day to show, e.g. 2024-01-15")
+	serverFlag := fset.String("server", serverURL(), "URL of the running wls server used to sync entries")
+	fset.Parse(args)
+
+	path, err := entryFilePath(viper.GetString("wls.app.dataDir"), *dateFlag)
+	if err != nil {
+		return fmt.Errorf("--date must be a date like 2024-01-15")
+	}
+
+	m := tuiModel{
+		path:    path,
+		date:    *dateFlag,
+		syncURL: strings.TrimSuffix(*serverFlag, "/") + "/" + viper.GetString("wls.server.apiVersion") + "/sync",
+	}
+	m.reload()
+
+	_, err = tea.NewProgram(m).Run()
+	return err
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// validateEntries returns the problems found in the entries of a day.
+func validateEntries(entries []TimeEntry, knownTags []string) []string {
+	problems := make([]string, 0)
+	for i, entry := range entries {
+		if entry.Hours < 0 {
+			problems = append(problems, fmt.Sprintf("entry %d (%s): negative hours %.2f", i, entry.ID, entry.Hours))
+		}
+		if entry.Note == "" {
+			problems = append(problems, fmt.Sprintf("entry %d (%s): empty note", i, entry.ID))
+		}
+		for _, tag := range entry.Tags {
+			if !slices.Contains(knownTags, tag.Name) {
+				problems = append(problems, fmt.Sprintf("entry %d (%s): unknown tag %s", i, entry.ID, tag.Name))
+			}
+		}
+	}
+
+	return problems
+}
+
+// dataYears returns the first and last year of the entry files stored in dataDir.
+func dataYears(dataDir string) (int, int, error) {
+	first, last := 0, 0
+	err := filepath.WalkDir(dataDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		day, err := time.Parse("2006-01-02", strings.TrimSuffix(d.Name(), ".json"))
+		if d.IsDir() || filepath.Ext(d.Name()) != ".json" || err != nil {
+			return nil
+		}
+		if first == 0 || day.Year() < first {
+			first = day.Year()
+		}
+		last = max(last, day.Year())
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if first == 0 {
+		return 0, 0, fmt.Errorf("no entries found in %s", dataDir)
+	}
+
+	return first, last, nil
+}
+
+// runValidate prints all files of the date range given in args which can not be decoded
+// or contain invalid entries. The whole data directory is checked if no range is given.
+func runValidate(args []string) error {
+	fset := flag.NewFlagSet("validate", flag.ExitOnError)
+	fromFlag := fset.String("from", "", "first date to check, e.g. 2024-01-01")
+	toFlag := fset.String("to", "", "last date to check, e.g. 2024-03-31")
+	fset.Parse(args)
+
+	dataDir := viper.GetString("wls.app.dataDir")
+	var from, to time.Time
+	if *fromFlag == "" || *toFlag == "" {
+		first, last, err := dataYears(dataDir)
+		if err != nil {
+			return err
+		}
+		from = time.Date(first, time.January, 1, 0, 0, 0, 0, time.UTC)
+		to = time.Date(last, time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	var err error
+	if *fromFlag != "" {
+		if from, err = time.Parse("2006-01-02", *fromFlag); err != nil {
+			return fmt.Errorf("--from must be a date like 2024-01-01")
+		}
+	}
+	if *toFlag != "" {
+		if to, err = time.Parse("2006-01-02", *toFlag); err != nil {
+			return fmt.Errorf("--to must be a date like 2024-03-31")
+		}
+	}
+
+	knownTags := viper.GetStringSlice("wls.app.knownTags")
+	files, invalid := 0, 0
+	err = walkFiles(dataDir, from, to, func(date, path string) error {
+		files++
+
+		entries, err := readEntries(path)
+		if err != nil {
+			invalid++
+			fmt.Printf("%s: %v\n", path, err)
+			return nil
+		}
+
+		problems := validateEntries(entries, knownTags)
+		if len(problems) > 0 {
+			invalid++
+		}
+		for _, problem := range problems {
+			fmt.Printf("%s: %s\n", path, problem)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d files checked, %d invalid\n", files, invalid)
+	if invalid > 0 {
+		return fmt.Errorf("%d invalid files found", invalid)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// walkFiles calls fn with the path of every day between from and to (inclusive)
+// for which a file is stored in dataDir. Missing directories of the data layout are skipped.
+// Walking stops at the first error returned by fn.
+func walkFiles(dataDir string, from, to time.Time, fn func(date, path string) error) error {
+	first := from.Format("2006-01-02")
+	last := to.Format("2006-01-02")
+
+	layout := dataLayout()
+	seen := make(map[string]bool)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		dir := filepath.Join(dataDir, formatLayout(layout, day))
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+
+		for _, file := range files {
+			date := strings.TrimSuffix(file.Name(), ".json")
+			if file.IsDir() || date == file.Name() || date < first || date > last {
+				continue
+			}
+
+			if err := fn(date, filepath.Join(dir, file.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// readEntries returns the entries stored in the file at path.
+func readEntries(path string) ([]TimeEntry, error) {
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []TimeEntry
+	if err := json.Unmarshal(dat, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// walkDateRange calls fn with the entries of every day between from and to (inclusive)
+// for which entries are stored in dataDir. Missing directories of the data layout are skipped.
+// Walking stops at the first error returned by fn.
+func walkDateRange(dataDir string, from, to time.Time, fn func(date string, entries []TimeEntry) error) error {
+	return walkFiles(dataDir, from, to, func(date, path string) error {
+		entries, err := readEntries(path)
+		if err != nil {
+			return fmt.Errorf("failed to decode entries of %s: %w", date, err)
+		}
+
+		return fn(date, entries)
+	})
+}
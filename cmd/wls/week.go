@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"html"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	rm "github.com/nixys/nxs-go-redmine/v5"
+	"github.com/spf13/viper"
+)
+
+// RemoteSyncer is the part of the redmine client used to look up issues.
+type RemoteSyncer interface {
+	GetIssue(id int64) (*rm.IssueObject, error)
+}
+
+// prefetchWorkers is the maximum number of concurrent issue lookups.
+const prefetchWorkers = 5
+
+// fetchIssues looks up all issueIDs concurrently and returns the issues by ID
+// and the number of lookups that failed or were cancelled.
+// Failed lookups are logged and missing in the result.
+func fetchIssues(ctx context.Context, rc RemoteSyncer, issueIDs []string) (map[string]*rm.IssueObject, int) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, prefetchWorkers)
+
+	ids := make(map[string]int64, len(issueIDs))
+	for _, issueID := range issueIDs {
+		id, err := strconv.ParseInt(strings.TrimPrefix(issueID, "#"), 10, 64)
+		if err != nil {
+			slog.Warn("Invalid issue ID", "issueID", issueID)
+			continue
+		}
+		ids[issueID] = id
+	}
+
+	issues := make(map[string]*rm.IssueObject, len(ids))
+	for _, issueID := range issueIDs {
+		id, ok := ids[issueID]
+		if !ok {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Warn("Issue lookup cancelled", "error", ctx.Err())
+			wg.Wait()
+			return issues, len(ids) - len(issues)
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			issue, err := rc.GetIssue(id)
+			if err != nil {
+				slog.Warn("Failed to get issue", "issueID", issueID, "error", err)
+				return
+			}
+
+			mu.Lock()
+			issues[issueID] = issue
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return issues, len(ids) - len(issues)
+}
+
+// prefetchIssueTitles looks up the subjects of all issueIDs concurrently.
+// Failed lookups are logged and missing in the result.
+func prefetchIssueTitles(ctx context.Context, rc RemoteSyncer, issueIDs []string) map[string]string {
+	titles := make(map[string]string, len(issueIDs))
+	issues, _ := fetchIssues(ctx, rc, issueIDs)
+	for issueID, issue := range issues {
+		titles[issueID] = issue.Subject
+	}
+
+	return titles
+}
+
+// week renders the entries from monday to friday of the week of the date parameter, today by default.
+// The issue titles are looked up once for all entries of the week.
+func (srv *Server) week(w http.ResponseWriter, r *http.Request) {
+	slog.Debug("week triggered")
+
+	day := time.Now()
+	if date := r.URL.Query().Get("date"); date != "" {
+		var err error
+		if day, err = time.Parse("2006-01-02", date); err != nil {
+			http.Error(w, "Date parameter must be a date like 2024-01-15", http.StatusBadRequest)
+			return
+		}
+	}
+	monday := time.Date(day.Year(), day.Month(), day.Day()-(int(day.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+	friday := monday.AddDate(0, 0, 4)
+
+	days := make(map[string][]TimeEntry)
+	issueIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	err := walkDateRange(viper.GetString("wls.app.dataDir"), monday, friday, func(date string, entries []TimeEntry) error {
+		days[date] = entries
+		for _, entry := range entries {
+			if issueID := findInTags(entry.Tags, "issue"); issueID != "" && !seen[issueID] {
+				seen[issueID] = true
+				issueIDs = append(issueIDs, issueID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		http.Error(w, "Failed to read entries", http.StatusInternalServerError)
+		slog.Error("Failed to read entries", "error", err)
+		return
+	}
+
+	titles := make(map[string]string)
+	if len(issueIDs) > 0 {
+		rc, err := srv.redmineClient()
+		if err != nil {
+			slog.Warn("Failed to create Redmine client, issue titles are missing", "error", err)
+		} else {
+			titles = prefetchIssueTitles(r.Context(), rc, issueIDs)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte("<h1>Week " + monday.Format("2006-01-02") + "</h1>"))
+	for d := monday; !d.After(friday); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		entries, ok := days[date]
+		if !ok {
+			continue
+		}
+
+		w.Write([]byte("<h2>" + d.Format("Monday") + " " + date + "</h2>"))
+		w.Write([]byte("<table border='1'><tr><th>Hours</th><th>Issue</th><th>Note</th></tr>"))
+		for _, entry := range entries {
+			issueID := findInTags(entry.Tags, "issue")
+			issue := html.EscapeString(issueID)
+			if title, ok := titles[issueID]; ok {
+				issue += " " + html.EscapeString(title)
+			}
+			w.Write([]byte("<tr><td>" + strconv.FormatFloat(entry.Hours, 'f', 2, 64) + "</td><td>" + issue + "</td><td>" + html.EscapeString(entry.Note) + "</td></tr>"))
+		}
+		w.Write([]byte("</table>"))
+	}
+}
@@ -0,0 +1,82 @@
+package redmine
+
+import (
+	"fmt"
+	"sync"
+)
+
+// defaultBulkWorkers is the number of parallel requests used by BulkCreateTimeEntries.
+const defaultBulkWorkers = 4
+
+// BulkError is returned by BulkCreateTimeEntries if at least one entry failed.
+// Errors is parallel to the entries, successful entries have a nil error.
+type BulkError struct {
+	Errors []error
+}
+
+func (e *BulkError) Error() string {
+	failed := 0
+	for _, err := range e.Errors {
+		if err != nil {
+			failed++
+		}
+	}
+
+	return fmt.Sprintf("%d of %d time entries failed", failed, len(e.Errors))
+}
+
+// Unwrap returns the errors of the failed entries.
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0)
+	for _, err := range e.Errors {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// BulkCreateTimeEntries creates the time entries using BulkWorkers parallel requests.
+// The returned IDs are parallel to entries with -1 for failed entries, which are
+// reported by a *BulkError.
+func (c *Client) BulkCreateTimeEntries(entries []TimeEntry) ([]int64, error) {
+	workers := c.BulkWorkers
+	if workers <= 0 {
+		workers = defaultBulkWorkers
+	}
+
+	IDs := make([]int64, len(entries))
+	errs := make([]error, len(entries))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(entries)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				ID, err := c.CreateTimeEntry(entries[i])
+				if err != nil {
+					IDs[i], errs[i] = -1, fmt.Errorf("time entry %d: %w", i, err)
+					continue
+				}
+				IDs[i] = ID
+			}
+		}()
+	}
+
+	for i := range entries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return IDs, &BulkError{Errors: errs}
+		}
+	}
+
+	return IDs, nil
+}
@@ -1,12 +1,19 @@
 package redmine
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	redmine "github.com/nixys/nxs-go-redmine/v5"
 	"github.com/sanity-io/litter"
@@ -19,9 +26,52 @@ type Client struct {
 
 	Dry bool
 
-	api *redmine.Context
+	// BulkWorkers is the number of parallel requests of BulkCreateTimeEntries, 4 if not set.
+	// It should not exceed the connections of the client, see WithMaxConns.
+	BulkWorkers int
+
+	// StatusTTL is the time issue statuses are cached. Zero caches them for the lifetime of the client.
+	StatusTTL time.Duration
+
+	http      *http.Client
+	transport *http.Transport
+
+	// logger traces requests if set, see WithTracing.
+	logger *slog.Logger
+
+	// switchUser is the login sent as X-Redmine-Switch-User, see ImpersonateUser.
+	switchUser string
+
+	// statuses and customFields are shared with impersonated copies of the client.
+	statuses     *statusCache
+	customFields *customFieldCache
+
+	// currentUser is not shared with impersonated copies, see GetCurrentUser.
+	currentUser *userCache
+}
+
+// userCache holds the user fetched by GetCurrentUser.
+type userCache struct {
+	mu   sync.Mutex
+	user *redmine.UserObject
+}
+
+// statusCache holds the issue statuses fetched by GetIssueStatuses.
+type statusCache struct {
+	mu       sync.Mutex
+	statuses []redmine.IssueStatusObject
+	fetched  time.Time
 }
 
+// ErrStatusNotFound is returned if no issue status matches the requested name.
+var ErrStatusNotFound = errors.New("status not found")
+
+// ErrUserNotFound is returned if no user has the requested login.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrNoClosedStatus is returned if redmine has no issue status which closes an issue.
+var ErrNoClosedStatus = errors.New("no closed status found")
+
 func (c *Client) getIssueID(issueIDs []string) (int64, error) {
 	for _, ID := range issueIDs {
 		if ID[:len(c.Prefix)] == c.Prefix {
@@ -40,12 +90,15 @@ func (c *Client) getIssueID(issueIDs []string) (int64, error) {
 }
 
 func (c *Client) GetActivityID(projectID, activityName string) (int64, error) {
-	project, code, err := c.api.ProjectSingleGet(
-		projectID,
-		redmine.ProjectSingleGetRequest{
-			Includes: []redmine.ProjectInclude{redmine.ProjectIncludeTimeEntryActivities},
-		},
+	var result struct {
+		Project redmine.ProjectObject `json:"project"`
+	}
+	code, err := c.get(
+		"/projects/"+url.PathEscape(projectID)+".json",
+		url.Values{"include": {string(redmine.ProjectIncludeTimeEntryActivities)}},
+		&result,
 	)
+	project := result.Project
 	if err != nil {
 		return 0, fmt.Errorf("error getting project %s: %w", projectID, err)
 	}
@@ -74,12 +127,58 @@ type TimeEntry struct {
 	ActivityID string
 	IsRedmine  bool
 	IsJira     bool
+
+	// CustomFields maps custom field names or IDs to their values.
+	CustomFields map[string]string
 }
 
-func (c *Client) Log(te TimeEntry) error {
+// maxCommentLength is the maximum length of a time entry comment accepted by redmine.
+const maxCommentLength = 255
+
+// Validate checks the fields required to create the time entry in redmine.
+func (te TimeEntry) Validate() error {
+	if len(te.IssueIDs) == 0 {
+		return fmt.Errorf("invalid time entry: no issue id given")
+	}
+	if te.Duration <= 0 {
+		return fmt.Errorf("invalid time entry: duration must be positive, got %v", te.Duration)
+	}
+	if te.ActivityID == "" {
+		return fmt.Errorf("invalid time entry: no activity id given")
+	}
+	if te.Start.IsZero() {
+		return fmt.Errorf("invalid time entry: no start given")
+	}
+	if n := utf8.RuneCountInString(te.Comment); n > maxCommentLength {
+		return fmt.Errorf("invalid time entry: comment has %d characters, at most %d are allowed", n, maxCommentLength)
+	}
+
+	return nil
+}
+
+// timeEntryCreateObject extends the time entry with custom fields, which are not
+// supported by the redmine package.
+type timeEntryCreateObject struct {
+	redmine.TimeEntryCreateObject
+	CustomFields []redmine.CustomFieldUpdateObject `json:"custom_fields,omitempty"`
+}
+
+// timeEntryCreate is the body of a time entry create request.
+type timeEntryCreate struct {
+	TimeEntry timeEntryCreateObject `json:"time_entry"`
+}
+
+// CreateTimeEntry creates the time entry and returns the ID of the created entry.
+// The entry is validated before any request is sent.
+// In dry mode the entry is only printed and 0 is returned.
+func (c *Client) CreateTimeEntry(te TimeEntry) (int64, error) {
+	if err := te.Validate(); err != nil {
+		return 0, err
+	}
+
 	ID, err := c.getIssueID(te.IssueIDs)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	issueID := int64(ID)
@@ -87,51 +186,112 @@ func (c *Client) Log(te TimeEntry) error {
 	AID := te.ActivityID
 	activityID, err := strconv.ParseInt(AID, 10, 64)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	date := te.Start.Format("2006-01-02")
 
-	teo := redmine.TimeEntryCreateObject{
-		IssueID:    &issueID,
-		ActivityID: activityID,
-		Hours:      te.Duration,
-		SpentOn:    &date,
-		Comments:   te.Comment,
+	customFields, err := c.customFieldValues(te.CustomFields)
+	if err != nil {
+		return 0, err
+	}
+
+	teo := timeEntryCreateObject{
+		TimeEntryCreateObject: redmine.TimeEntryCreateObject{
+			IssueID:    &issueID,
+			ActivityID: activityID,
+			Hours:      te.Duration,
+			SpentOn:    &date,
+			Comments:   te.Comment,
+		},
+		CustomFields: customFields,
 	}
 
 	if c.Dry {
 		litter.Dump(teo)
-		return nil
+		return 0, nil
 	}
 
-	log.Print("sending ... ")
-	_, code, err := c.api.TimeEntryCreate(
-		redmine.TimeEntryCreate{
+	c.log().Info("sending time entry", "issue", issueID, "hours", te.Duration)
+	var result struct {
+		TimeEntry redmine.TimeEntryObject `json:"time_entry"`
+	}
+	code, err := c.send(
+		http.MethodPost,
+		"/time_entries.json",
+		timeEntryCreate{
 			TimeEntry: teo,
 		},
+		&result,
+		http.StatusCreated,
 	)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	if code != http.StatusCreated {
-		return fmt.Errorf("could not log time entry")
+		return 0, fmt.Errorf("could not log time entry")
 	}
 
-	log.Println("seemed ok with code %s", code)
+	c.log().Info("time entry created", "issue", issueID, "code", code)
 
-	return nil
+	return result.TimeEntry.ID, nil
+}
+
+// Log creates the time entry. Invalid entries are rejected without a request, see TimeEntry.Validate.
+func (c *Client) Log(te TimeEntry) error {
+	_, err := c.CreateTimeEntry(te)
+	return err
+}
+
+// TimeEntryURL returns the link to the time entry with the given id.
+func (c *Client) TimeEntryURL(id int64) string {
+	return fmt.Sprintf("%s/time_entries/%d", strings.TrimSuffix(c.URL, "/"), id)
 }
 
-func (c *Client) WriteComment(id int64, comment string) error {
-	private := true
+// GetTimeEntry returns the time entry with the given id, e.g. to check an entry created by Log.
+// Redmine only stores the day of an entry, so Start is midnight of SpentOn and End is not set.
+func (c *Client) GetTimeEntry(id int64) (*TimeEntry, error) {
+	var result struct {
+		TimeEntry redmine.TimeEntryObject `json:"time_entry"`
+	}
+	code, err := c.get("/time_entries/"+strconv.FormatInt(id, 10)+".json", nil, &result)
+	if code == http.StatusForbidden {
+		return nil, fmt.Errorf("access forbidden on time entry %d: %d", id, code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting time entry %d: %w", id, err)
+	}
+
+	entry := result.TimeEntry
+	start, err := time.Parse("2006-01-02", entry.SpentOn)
+	if err != nil {
+		return nil, fmt.Errorf("error getting time entry %d: invalid spent_on: %w", id, err)
+	}
+
+	te := &TimeEntry{
+		ID:         strconv.FormatInt(entry.ID, 10),
+		Start:      start,
+		Duration:   entry.Hours,
+		Hours:      time.Duration(entry.Hours * float64(time.Hour)),
+		Comment:    entry.Comments,
+		ActivityID: strconv.FormatInt(entry.Activity.ID, 10),
+		IsRedmine:  true,
+	}
+	if entry.Issue.ID != 0 {
+		te.IssueIDs = []string{c.Prefix + strconv.FormatInt(entry.Issue.ID, 10)}
+	}
+
+	return te, nil
+}
+
+// WriteComment adds the comment as note to the issue with the given id.
+// If private is set, the note is only visible to users with the permission to see private notes.
+func (c *Client) WriteComment(id int64, comment string, private bool) error {
 	payload := redmine.IssueUpdateObject{
 		Notes:        &comment,
 		PrivateNotes: &private,
 	}
-	code, err := c.api.IssueUpdate(id, redmine.IssueUpdate{
-		Issue: payload,
-	})
+	code, err := c.updateIssue(id, payload)
 	if code == 403 {
 		return fmt.Errorf("access forbidden on %d: %d", id, code)
 	}
@@ -145,8 +305,75 @@ func (c *Client) WriteComment(id int64, comment string) error {
 	return nil
 }
 
+// UploadAttachment uploads the content of r and returns the token used to attach
+// the upload to an issue, see AttachFile.
+func (c *Client) UploadAttachment(filename string, r io.Reader) (string, error) {
+	if c.Dry {
+		litter.Dump(filename)
+		return "", nil
+	}
+
+	req, err := c.newRequest(http.MethodPost, "/uploads.json", url.Values{"filename": {filepath.Base(filename)}}, r)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	var result struct {
+		Upload redmine.AttachmentUploadObject `json:"upload"`
+	}
+	code, err := c.do(req, &result, http.StatusCreated)
+	if err != nil {
+		return "", fmt.Errorf("error uploading %s: %w", filename, err)
+	}
+	if code != http.StatusCreated {
+		return "", fmt.Errorf("error uploading %s: %d", filename, code)
+	}
+
+	return result.Upload.Token, nil
+}
+
+// AttachFile attaches the upload with the given token to the issue. The description
+// is added as note because the upload object does not carry a description.
+func (c *Client) AttachFile(issueID int64, token, filename, description string) error {
+	uploads := []redmine.AttachmentUploadObject{
+		{
+			Token:       token,
+			Filename:    filepath.Base(filename),
+			ContentType: mime.TypeByExtension(filepath.Ext(filename)),
+		},
+	}
+	payload := redmine.IssueUpdateObject{
+		Uploads: &uploads,
+	}
+	if description != "" {
+		payload.Notes = &description
+	}
+
+	if c.Dry {
+		litter.Dump(payload)
+		return nil
+	}
+
+	code, err := c.updateIssue(issueID, payload)
+	if code == 403 {
+		return fmt.Errorf("access forbidden on %d: %d", issueID, code)
+	}
+	if code != 204 {
+		return fmt.Errorf("unexpected code on %d: %d", issueID, code)
+	}
+	if err != nil {
+		return fmt.Errorf("error attaching %s to issue %d: %s", filename, issueID, err)
+	}
+
+	return nil
+}
+
 func (c *Client) GetIssue(id int64) (*redmine.IssueObject, error) {
-	i, code, err := c.api.IssueSingleGet(id, redmine.IssueSingleGetRequest{})
+	var result struct {
+		Issue redmine.IssueObject `json:"issue"`
+	}
+	code, err := c.get("/issues/"+strconv.FormatInt(id, 10)+".json", nil, &result)
 	if code == 403 {
 		return nil, fmt.Errorf("access forbidden on %d: %d", id, code)
 	}
@@ -157,26 +384,432 @@ func (c *Client) GetIssue(id int64) (*redmine.IssueObject, error) {
 		return nil, fmt.Errorf("error getting issue %d: %s", id, err)
 	}
 
-	return &i, nil
+	return &result.Issue, nil
+}
+
+// getProjectID resolves a project identifier (e.g. "myapp") or a numeric
+// project ID to the numeric project ID.
+func (c *Client) getProjectID(projectID string) (int64, error) {
+	if ID, err := strconv.ParseInt(projectID, 10, 64); err == nil {
+		return ID, nil
+	}
+
+	var result struct {
+		Project redmine.ProjectObject `json:"project"`
+	}
+	code, err := c.get("/projects/"+url.PathEscape(projectID)+".json", nil, &result)
+	if err != nil {
+		return 0, fmt.Errorf("error getting project %s: %w", projectID, err)
+	}
+	if code != http.StatusOK {
+		return 0, fmt.Errorf("error getting project %s: %d", projectID, code)
+	}
+
+	return result.Project.ID, nil
+}
+
+// GetUserByLogin returns the user with exactly the given login.
+// ErrUserNotFound is returned if no user matches.
+func (c *Client) GetUserByLogin(login string) (*redmine.UserObject, error) {
+	users, code, err := getAll[redmine.UserObject](c, "/users.json", url.Values{"name": {login}}, "users")
+	if err != nil {
+		return nil, fmt.Errorf("error getting user %s: %w", login, err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error getting user %s: %d", login, code)
+	}
+
+	for _, user := range users {
+		if user.Login == login {
+			return &user, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUserNotFound, login)
 }
 
-func NewClient(URL, key, prefix string, dry bool) (*Client, error) {
+// GetCurrentUser returns the authenticated user, i.e. the owner of the API key
+// or the impersonated user. The result is cached for the lifetime of the client.
+func (c *Client) GetCurrentUser() (*redmine.UserObject, error) {
+	c.currentUser.mu.Lock()
+	defer c.currentUser.mu.Unlock()
+
+	if c.currentUser.user != nil {
+		return c.currentUser.user, nil
+	}
+
+	var result struct {
+		User redmine.UserObject `json:"user"`
+	}
+	code, err := c.get("/users/current.json", nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error getting current user: %w", err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error getting current user: %d", code)
+	}
+	c.currentUser.user = &result.User
+
+	return c.currentUser.user, nil
+}
+
+// IssueOption sets an optional field of an issue created with CreateIssue.
+type IssueOption func(*redmine.IssueCreateObject)
+
+// WithAssignee assigns the new issue to the user with the given ID.
+func WithAssignee(userID int64) IssueOption {
+	return func(i *redmine.IssueCreateObject) {
+		i.AssignedToID = &userID
+	}
+}
+
+// WithStatus sets the status of the new issue instead of the default status of the tracker.
+func WithStatus(statusID int64) IssueOption {
+	return func(i *redmine.IssueCreateObject) {
+		i.StatusID = &statusID
+	}
+}
+
+// WithPriority sets the priority of the new issue.
+func WithPriority(priorityID int64) IssueOption {
+	return func(i *redmine.IssueCreateObject) {
+		i.PriorityID = &priorityID
+	}
+}
+
+// WithVersion sets the target version of the new issue.
+func WithVersion(versionID int64) IssueOption {
+	return func(i *redmine.IssueCreateObject) {
+		i.FixedVersionID = &versionID
+	}
+}
+
+// WithCustomField sets the value of the custom field with the given ID, it can be used multiple times.
+func WithCustomField(id int64, value string) IssueOption {
+	return func(i *redmine.IssueCreateObject) {
+		if i.CustomFields == nil {
+			i.CustomFields = &[]redmine.CustomFieldUpdateObject{}
+		}
+		*i.CustomFields = append(*i.CustomFields, redmine.CustomFieldUpdateObject{ID: id, Value: value})
+	}
+}
+
+// CreateIssue creates a new issue in the given project and returns it with its new ID.
+// A *ValidationError is returned if redmine rejects the issue, e.g. because of a missing required field.
+func (c *Client) CreateIssue(projectID, subject, description string, opts ...IssueOption) (*redmine.IssueObject, error) {
+	pid, err := c.getProjectID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := redmine.IssueCreateObject{
+		ProjectID:   pid,
+		Subject:     subject,
+		Description: &description,
+	}
+
+	for _, opt := range opts {
+		opt(&payload)
+	}
+
+	if c.Dry {
+		litter.Dump(payload)
+		return &redmine.IssueObject{Subject: subject, Description: description}, nil
+	}
+
+	var result struct {
+		Issue redmine.IssueObject `json:"issue"`
+	}
+	code, err := c.send(http.MethodPost, "/issues.json", redmine.IssueCreate{
+		Issue: payload,
+	}, &result, http.StatusCreated)
+	if code == 403 {
+		return nil, fmt.Errorf("access forbidden on project %s: %d", projectID, code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating issue in project %s: %w", projectID, err)
+	}
+	if code != http.StatusCreated {
+		return nil, fmt.Errorf("unexpected code creating issue in project %s: %d", projectID, code)
+	}
+
+	return &result.Issue, nil
+}
+
+// AmbiguousStatusError is returned if a status name matches more than one issue status.
+type AmbiguousStatusError struct {
+	Name       string
+	Candidates []redmine.IssueStatusObject
+}
+
+func (e *AmbiguousStatusError) Error() string {
+	return fmt.Sprintf("status %s is ambiguous: %d statuses match", e.Name, len(e.Candidates))
+}
+
+// GetIssueStatuses returns all issue statuses. The result is cached on the client, see StatusTTL.
+func (c *Client) GetIssueStatuses() ([]redmine.IssueStatusObject, error) {
+	c.statuses.mu.Lock()
+	defer c.statuses.mu.Unlock()
+
+	if c.statuses.statuses != nil && (c.StatusTTL == 0 || time.Since(c.statuses.fetched) < c.StatusTTL) {
+		return c.statuses.statuses, nil
+	}
+
+	var result struct {
+		IssueStatuses []redmine.IssueStatusObject `json:"issue_statuses"`
+	}
+	code, err := c.get("/issue_statuses.json", nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error getting issue statuses: %w", err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error getting issue statuses: %d", code)
+	}
+	c.statuses.statuses = result.IssueStatuses
+	c.statuses.fetched = time.Now()
+
+	return c.statuses.statuses, nil
+}
+
+// findStatusID resolves a status name to its ID. An exact (case insensitive) match
+// is preferred over a partial match. ErrStatusNotFound is returned if nothing matches
+// and an *AmbiguousStatusError with the candidates if more than one status matches partially.
+func (c *Client) findStatusID(statusName string) (int64, error) {
+	statuses, err := c.GetIssueStatuses()
+	if err != nil {
+		return 0, err
+	}
+
+	candidates := make([]redmine.IssueStatusObject, 0)
+	for _, status := range statuses {
+		if strings.EqualFold(status.Name, statusName) {
+			return status.ID, nil
+		}
+		if strings.Contains(strings.ToLower(status.Name), strings.ToLower(statusName)) {
+			candidates = append(candidates, status)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return 0, fmt.Errorf("%w: %s", ErrStatusNotFound, statusName)
+	case 1:
+		return candidates[0].ID, nil
+	default:
+		return 0, &AmbiguousStatusError{Name: statusName, Candidates: candidates}
+	}
+}
+
+// UpdateIssueStatus sets the status of the issue with the given id.
+func (c *Client) UpdateIssueStatus(id int64, statusName string) error {
+	statusID, err := c.findStatusID(statusName)
+	if err != nil {
+		return err
+	}
+
+	payload := redmine.IssueUpdateObject{
+		StatusID: &statusID,
+	}
+
+	if c.Dry {
+		litter.Dump(payload)
+		return nil
+	}
+
+	code, err := c.updateIssue(id, payload)
+	if code == 403 {
+		return fmt.Errorf("access forbidden on %d: %d", id, code)
+	}
+	if code != 204 {
+		return fmt.Errorf("unexpected code on %d: %d", id, code)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating status of issue %d: %s", id, err)
+	}
+
+	return nil
+}
+
+// AssignIssue assigns the issue with the given id to the user with the given login.
+func (c *Client) AssignIssue(issueID int64, userLogin string) error {
+	user, err := c.GetUserByLogin(userLogin)
+	if err != nil {
+		return err
+	}
+
+	payload := redmine.IssueUpdateObject{
+		AssignedToID: &user.ID,
+	}
+
+	if c.Dry {
+		litter.Dump(payload)
+		return nil
+	}
+
+	code, err := c.updateIssue(issueID, payload)
+	if code == http.StatusForbidden {
+		return fmt.Errorf("access forbidden on %d: %d", issueID, code)
+	}
+	if err != nil {
+		return fmt.Errorf("error assigning issue %d to %s: %w", issueID, userLogin, err)
+	}
+
+	return nil
+}
+
+// closedStatusID returns the ID of the status used to close issues. A status named "Closed"
+// is preferred over statuses containing "closed" and those over any other closed status.
+func (c *Client) closedStatusID() (int64, error) {
+	statuses, err := c.GetIssueStatuses()
+	if err != nil {
+		return 0, err
+	}
+
+	var partial, first *redmine.IssueStatusObject
+	for i, status := range statuses {
+		if !status.IsClosed {
+			continue
+		}
+		if strings.EqualFold(status.Name, "closed") {
+			return status.ID, nil
+		}
+		if partial == nil && strings.Contains(strings.ToLower(status.Name), "closed") {
+			partial = &statuses[i]
+		}
+		if first == nil {
+			first = &statuses[i]
+		}
+	}
+
+	switch {
+	case partial != nil:
+		return partial.ID, nil
+	case first != nil:
+		return first.ID, nil
+	default:
+		return 0, ErrNoClosedStatus
+	}
+}
+
+// CloseIssue sets the issue with the given id to the closed status and adds the comment as note.
+// The comment is optional and will be ignored if it is empty.
+func (c *Client) CloseIssue(id int64, comment string) error {
+	statusID, err := c.closedStatusID()
+	if err != nil {
+		return err
+	}
+
+	payload := redmine.IssueUpdateObject{
+		StatusID: &statusID,
+	}
+	if comment != "" {
+		payload.Notes = &comment
+	}
+
+	if c.Dry {
+		litter.Dump(payload)
+		return nil
+	}
+
+	code, err := c.updateIssue(id, payload)
+	if code == http.StatusForbidden {
+		return fmt.Errorf("access forbidden on %d: %d", id, code)
+	}
+	if err != nil {
+		return fmt.Errorf("error closing issue %d: %w", id, err)
+	}
+
+	return nil
+}
+
+// GetProjectMembers returns all memberships of the given project. The project
+// may be given by identifier (e.g. "myapp") or numeric ID.
+func (c *Client) GetProjectMembers(projectID string) ([]redmine.MembershipObject, error) {
+	members, code, err := getAll[redmine.MembershipObject](c, "/projects/"+url.PathEscape(projectID)+"/memberships.json", nil, "memberships")
+	if err != nil {
+		return nil, fmt.Errorf("error getting members of project %s: %w", projectID, err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error getting members of project %s: %d", projectID, code)
+	}
+
+	return members, nil
+}
+
+// ListMyIssues returns all open issues assigned to the authenticated user.
+func (c *Client) ListMyIssues() ([]redmine.IssueObject, error) {
+	filters := url.Values{
+		"assigned_to_id": {"me"},
+		"status_id":      {"open"},
+	}
+
+	issues, code, err := getAll[redmine.IssueObject](c, "/issues.json", filters, "issues")
+	if err != nil {
+		return nil, fmt.Errorf("error listing issues: %w", err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error listing issues: %d", code)
+	}
+
+	return issues, nil
+}
+
+// NewClient creates a client for the redmine instance at URL. Without options
+// the proxy is taken from HTTP_PROXY / HTTPS_PROXY.
+// The client opens at most 10 connections to the redmine host, so at most 10 requests
+// are in flight at a time and further concurrent requests wait for a free connection.
+// WithMaxConns changes the limit.
+func NewClient(URL, key, prefix string, dry bool, opts ...Option) (*Client, error) {
 	if URL == "" || key == "" {
 		return nil, fmt.Errorf("failed to create new client: make sure to provide URL and key.")
 	}
 
-	api := redmine.Init(
-		redmine.Settings{
-			Endpoint: URL,
-			APIKey:   key,
-		},
-	)
+	c := &Client{
+		APIKey:       key,
+		URL:          URL,
+		Prefix:       prefix,
+		Dry:          dry,
+		transport:    http.DefaultTransport.(*http.Transport).Clone(),
+		statuses:     &statusCache{},
+		customFields: &customFieldCache{},
+		currentUser:  &userCache{},
+	}
+	// the default of 2 idle connections per host would close the connections of parallel requests
+	c.transport.MaxIdleConnsPerHost = defaultMaxConns
+	c.transport.MaxConnsPerHost = defaultMaxConns
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, fmt.Errorf("failed to create new client: %w", err)
+		}
+	}
+	c.http = &http.Client{Transport: c.transport}
+
+	return c, nil
+}
+
+// ImpersonateUser returns a copy of the client which performs all requests as the
+// user with the given login. This requires the API key of an administrator.
+// The copy shares the HTTP connection with c, c itself is not modified.
+func (c *Client) ImpersonateUser(login string) *Client {
+	impersonated := *c
+	impersonated.switchUser = login
+	impersonated.currentUser = &userCache{}
 
-	return &Client{
-		APIKey: key,
-		URL:    URL,
-		Prefix: prefix,
-		Dry:    dry,
-		api:    api,
-	}, nil
+	return &impersonated
+}
+
+// log returns the tracing logger or the default logger.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+
+	return slog.Default()
+}
+
+// updateIssue sends the update of the issue with the given id.
+func (c *Client) updateIssue(id int64, payload redmine.IssueUpdateObject) (int, error) {
+	return c.send(http.MethodPut, "/issues/"+strconv.FormatInt(id, 10)+".json", redmine.IssueUpdate{
+		Issue: payload,
+	}, nil, http.StatusNoContent)
 }
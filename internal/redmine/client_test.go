@@ -0,0 +1,261 @@
+package redmine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	redmine "github.com/nixys/nxs-go-redmine/v5"
+)
+
+// newTestClient returns a client for a test server answering with handler.
+func newTestClient(t testing.TB, handler http.Handler, opts ...Option) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(srv.URL, "key", "#", false, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+// writeJSON writes v as JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// timeEntryMock stores the time entries created through the API in memory.
+type timeEntryMock struct {
+	mu      sync.Mutex
+	entries map[int64]redmine.TimeEntryObject
+}
+
+func (m *timeEntryMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.Method == http.MethodPost && r.URL.Path == "/time_entries.json" {
+		var create timeEntryCreate
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorsResult{Errors: []string{err.Error()}})
+			return
+		}
+
+		te := create.TimeEntry
+		entry := redmine.TimeEntryObject{
+			ID:       int64(len(m.entries) + 1),
+			Issue:    redmine.TimeEntryIssueObject{ID: *te.IssueID},
+			Activity: redmine.IDName{ID: te.ActivityID, Name: "Development"},
+			Hours:    te.Hours,
+			Comments: te.Comments,
+			SpentOn:  *te.SpentOn,
+		}
+		m.entries[entry.ID] = entry
+		writeJSON(w, http.StatusCreated, map[string]any{"time_entry": entry})
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/time_entries/"), ".json"), 10, 64)
+	if entry, ok := m.entries[id]; r.Method == http.MethodGet && err == nil && ok {
+		writeJSON(w, http.StatusOK, map[string]any{"time_entry": entry})
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+func TestTimeEntryRoundTrip(t *testing.T) {
+	mock := &timeEntryMock{entries: map[int64]redmine.TimeEntryObject{}}
+	c := newTestClient(t, mock)
+
+	te := TimeEntry{
+		IssueIDs:   []string{"#123"},
+		Start:      time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC),
+		Duration:   1.5,
+		Comment:    "fixed the login",
+		ActivityID: "9",
+	}
+
+	// dry mode only prints the entry
+	c.Dry = true
+	if id, err := c.CreateTimeEntry(te); err != nil || id != 0 {
+		t.Fatalf("dry CreateTimeEntry = %d, %v, want 0, nil", id, err)
+	}
+	if len(mock.entries) != 0 {
+		t.Fatalf("dry mode created %d entries", len(mock.entries))
+	}
+
+	c.Dry = false
+	id, err := c.CreateTimeEntry(te)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.GetTimeEntry(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := TimeEntry{
+		ID:         strconv.FormatInt(id, 10),
+		IssueIDs:   []string{"#123"},
+		Start:      time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Duration:   1.5,
+		Hours:      90 * time.Minute,
+		Comment:    "fixed the login",
+		ActivityID: "9",
+		IsRedmine:  true,
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("GetTimeEntry = %+v, want %+v", *got, want)
+	}
+
+	if _, err := c.GetTimeEntry(id + 1); err == nil {
+		t.Error("GetTimeEntry of a missing entry succeeded")
+	}
+}
+
+func TestGetProjectMembers(t *testing.T) {
+	const total = 150
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/projects/my%20app/memberships.json" || r.Header.Get("X-Redmine-API-Key") != "key" {
+			http.NotFound(w, r)
+			return
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		members := make([]redmine.MembershipObject, 0)
+		for id := offset + 1; id <= min(offset+limit, total); id++ {
+			members = append(members, redmine.MembershipObject{
+				ID:   int64(id),
+				User: &redmine.IDName{ID: int64(id), Name: "user " + strconv.Itoa(id)},
+			})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"memberships": members, "total_count": total})
+	}))
+
+	members, err := c.GetProjectMembers("my app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != total {
+		t.Fatalf("got %d members, want all %d pages", len(members), total)
+	}
+	for i, member := range members {
+		if member.ID != int64(i+1) || member.User == nil {
+			t.Fatalf("member %d = %+v, want the members in order", i, member)
+		}
+	}
+
+	if _, err := c.GetProjectMembers("unknown"); err == nil {
+		t.Error("GetProjectMembers of an unknown project succeeded")
+	}
+}
+
+func TestGetCurrentUser(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/current.json" {
+			http.NotFound(w, r)
+			return
+		}
+
+		login := r.Header.Get("X-Redmine-Switch-User")
+		if login == "" {
+			login = "owner"
+		}
+		mu.Lock()
+		requests[login]++
+		mu.Unlock()
+
+		writeJSON(w, http.StatusOK, map[string]any{"user": redmine.UserObject{ID: int64(len(login)), Login: login}})
+	}))
+
+	for range 3 {
+		user, err := c.GetCurrentUser()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Login != "owner" {
+			t.Errorf("login = %q, want the owner of the API key", user.Login)
+		}
+	}
+
+	// impersonated copies look up their own user
+	user, err := c.ImpersonateUser("sam").GetCurrentUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Login != "sam" {
+		t.Errorf("login = %q, want the impersonated user", user.Login)
+	}
+
+	if requests["owner"] != 1 || requests["sam"] != 1 {
+		t.Errorf("requests = %v, want one request per user", requests)
+	}
+}
+
+func TestTimeEntryValidate(t *testing.T) {
+	valid := TimeEntry{
+		IssueIDs:   []string{"#1"},
+		Start:      time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Duration:   1,
+		ActivityID: "9",
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(te *TimeEntry)
+		wantErr bool
+	}{
+		{"valid", func(te *TimeEntry) {}, false},
+		{"no issue", func(te *TimeEntry) { te.IssueIDs = nil }, true},
+		{"zero duration", func(te *TimeEntry) { te.Duration = 0 }, true},
+		{"negative duration", func(te *TimeEntry) { te.Duration = -1 }, true},
+		{"no activity", func(te *TimeEntry) { te.ActivityID = "" }, true},
+		{"no start", func(te *TimeEntry) { te.Start = time.Time{} }, true},
+		{"longest comment", func(te *TimeEntry) { te.Comment = strings.Repeat("a", maxCommentLength) }, false},
+		{"comment too long", func(te *TimeEntry) { te.Comment = strings.Repeat("a", maxCommentLength+1) }, true},
+		// the length is counted in characters, not bytes
+		{"longest unicode comment", func(te *TimeEntry) { te.Comment = strings.Repeat("ü", maxCommentLength) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			te := valid
+			tt.modify(&te)
+
+			if err := te.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateTimeEntryValidatesBeforeRequest(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	}))
+
+	if _, err := c.CreateTimeEntry(TimeEntry{IssueIDs: []string{"#1"}}); err == nil {
+		t.Error("CreateTimeEntry accepted an invalid entry")
+	}
+	if requests != 0 {
+		t.Errorf("sent %d requests for an invalid entry", requests)
+	}
+}
@@ -0,0 +1,77 @@
+package redmine
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	redmine "github.com/nixys/nxs-go-redmine/v5"
+)
+
+// customFieldCache holds the custom fields fetched by ListCustomFields.
+type customFieldCache struct {
+	mu     sync.Mutex
+	fields []redmine.CustomFieldObject
+}
+
+// ListCustomFields returns all custom fields. The result is cached for the lifetime of the client.
+// Listing custom fields requires administrator privileges.
+func (c *Client) ListCustomFields() ([]redmine.CustomFieldObject, error) {
+	c.customFields.mu.Lock()
+	defer c.customFields.mu.Unlock()
+
+	if c.customFields.fields != nil {
+		return c.customFields.fields, nil
+	}
+
+	var result struct {
+		CustomFields []redmine.CustomFieldObject `json:"custom_fields"`
+	}
+	code, err := c.get("/custom_fields.json", nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error getting custom fields: %w", err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error getting custom fields: %d", code)
+	}
+	c.customFields.fields = result.CustomFields
+
+	return c.customFields.fields, nil
+}
+
+// customFieldValues converts the values by custom field name (case insensitive) or ID to the request format.
+// Custom fields are only listed if a value is given by name.
+func (c *Client) customFieldValues(values map[string]string) ([]redmine.CustomFieldUpdateObject, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	result := make([]redmine.CustomFieldUpdateObject, 0, len(values))
+	for name, value := range values {
+		if ID, err := strconv.ParseInt(name, 10, 64); err == nil {
+			result = append(result, redmine.CustomFieldUpdateObject{ID: ID, Value: value})
+			continue
+		}
+
+		fields, err := c.ListCustomFields()
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, field := range fields {
+			if strings.EqualFold(field.Name, name) {
+				result = append(result, redmine.CustomFieldUpdateObject{ID: field.ID, Value: value})
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("custom field %s not found", name)
+		}
+	}
+
+	return result, nil
+}
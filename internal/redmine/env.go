@@ -0,0 +1,75 @@
+package redmine
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/viper"
+)
+
+// defaultConfigPrefixes are the viper keys searched for the redmine settings by NewClientFromEnv,
+// in priority order.
+var defaultConfigPrefixes = []string{"rmi.redmine.", "wls.redmine."}
+
+// lookupConfig returns the value of the environment variable env or the first
+// non-empty setting key of v below one of the prefixes.
+func lookupConfig(v *viper.Viper, prefixes []string, env, key string) string {
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+
+	for _, prefix := range prefixes {
+		if value := v.GetString(prefix + key); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// URLFromEnv returns the redmine URL NewClientFromEnv connects to, i.e. REDMINE_URL or
+// the url setting of the global viper instance.
+func URLFromEnv() string {
+	return lookupConfig(viper.GetViper(), defaultConfigPrefixes, "REDMINE_URL", "url")
+}
+
+// NewClientFromEnv creates a client from REDMINE_URL, REDMINE_API_KEY, REDMINE_PREFIX,
+// REDMINE_DRY_RUN, REDMINE_PROXY and REDMINE_INSECURE. Unset variables fall back to
+// the rmi.redmine.* and wls.redmine.* settings. The given options are applied last.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
+	return NewClientFromConfig(viper.GetViper(), defaultConfigPrefixes, opts...)
+}
+
+// NewClientFromConfig creates a client like NewClientFromEnv but falls back to the settings of v
+// below the given prefixes, e.g. "wls.redmine.", instead of the global viper instance.
+func NewClientFromConfig(v *viper.Viper, prefixes []string, opts ...Option) (*Client, error) {
+	URL := lookupConfig(v, prefixes, "REDMINE_URL", "url")
+	key := lookupConfig(v, prefixes, "REDMINE_API_KEY", "key")
+	if URL == "" || key == "" {
+		return nil, fmt.Errorf("no redmine credentials found in config or environment.")
+	}
+
+	var dry bool
+	if value := lookupConfig(v, prefixes, "REDMINE_DRY_RUN", "dryrun"); value != "" {
+		var err error
+		if dry, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid dry run setting %s: %w", value, err)
+		}
+	}
+
+	var insecure bool
+	if value := lookupConfig(v, prefixes, "REDMINE_INSECURE", "insecure"); value != "" {
+		var err error
+		if insecure, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid insecure setting %s: %w", value, err)
+		}
+	}
+
+	envOpts := []Option{WithInsecureSkipVerify(insecure)}
+	if proxy := lookupConfig(v, prefixes, "REDMINE_PROXY", "proxy"); proxy != "" {
+		envOpts = append(envOpts, WithProxy(proxy))
+	}
+
+	return NewClient(URL, key, lookupConfig(v, prefixes, "REDMINE_PREFIX", "prefix"), dry, append(envOpts, opts...)...)
+}
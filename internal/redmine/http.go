@@ -0,0 +1,280 @@
+package redmine
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Option configures the HTTP connection of a Client, see NewClient.
+type Option func(*Client) error
+
+// WithProxy sends all requests through the proxy at proxyURL instead of
+// the proxy configured by HTTP_PROXY / HTTPS_PROXY.
+func WithProxy(proxyURL string) Option {
+	return func(c *Client) error {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url %s: %w", proxyURL, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid proxy url %s: scheme and host are required", proxyURL)
+		}
+
+		c.transport.Proxy = http.ProxyURL(u)
+
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the server certificate,
+// e.g. for redmine instances with self-signed certificates.
+func WithInsecureSkipVerify(skip bool) Option {
+	return func(c *Client) error {
+		if c.transport.TLSClientConfig == nil {
+			c.transport.TLSClientConfig = &tls.Config{}
+		}
+		c.transport.TLSClientConfig.InsecureSkipVerify = skip
+
+		return nil
+	}
+}
+
+// defaultMaxConns is the number of connections to the redmine host kept by default, see WithMaxConns.
+const defaultMaxConns = 10
+
+// WithMaxConns limits the connections to the redmine host to n and keeps up to n idle
+// connections open for reuse. n should be at least BulkWorkers, otherwise parallel
+// requests of BulkCreateTimeEntries wait for a connection or open new ones.
+func WithMaxConns(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return fmt.Errorf("invalid max connections %d: must be positive", n)
+		}
+		c.transport.MaxIdleConnsPerHost = n
+		c.transport.MaxConnsPerHost = n
+
+		return nil
+	}
+}
+
+// WithTracing logs every request and response at debug level to logger.
+func WithTracing(logger *slog.Logger) Option {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
+// errorsResult is the body redmine returns for failed requests.
+type errorsResult struct {
+	Errors []string `json:"errors"`
+}
+
+// ValidationError is returned if redmine rejects a request with 422 Unprocessable Entity.
+// Errors contains the messages of redmine, e.g. "Subject cannot be blank".
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed: " + strings.Join(e.Errors, ", ")
+}
+
+// newRequest creates a request to the redmine API authenticated with the API key.
+func (c *Client) newRequest(method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	u := strings.TrimSuffix(c.URL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Redmine-API-Key", c.APIKey)
+	if c.switchUser != "" {
+		req.Header.Set("X-Redmine-Switch-User", c.switchUser)
+	}
+
+	return req, nil
+}
+
+// do sends the request and decodes the JSON response into out if the status code is the expected one.
+// The status code is returned in any case so callers can react to e.g. 403 or 404.
+// Requests answered with 429 Too Many Requests are retried up to maxAttempts times.
+func (c *Client) do(req *http.Request, out any, expected int) (int, error) {
+	tracing := c.logger != nil && c.logger.Enabled(req.Context(), slog.LevelDebug)
+
+	var resp *http.Response
+	var start time.Time
+	for attempt := 1; ; attempt++ {
+		if tracing {
+			c.logger.Debug("redmine request", "method", req.Method, "url", req.URL.String(), "attempt", attempt)
+		}
+
+		var err error
+		start = time.Now()
+		resp, err = c.http.Do(req)
+		if err != nil {
+			return 0, err
+		}
+
+		// a request body can only be sent again if it can be recreated
+		retry := req.Body == nil || req.GetBody != nil
+		if resp.StatusCode != http.StatusTooManyRequests || attempt == maxAttempts || !retry {
+			break
+		}
+
+		wait := handleRateLimit(resp)
+		resp.Body.Close()
+		c.log().Warn("redmine rate limit reached", "url", req.URL.String(), "wait", wait)
+		time.Sleep(wait)
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	defer resp.Body.Close()
+
+	// the body is only copied if tracing is enabled to count its size
+	var body io.Reader = resp.Body
+	if tracing {
+		var buf bytes.Buffer
+		body = io.TeeReader(resp.Body, &buf)
+		defer func() {
+			io.Copy(io.Discard, body)
+			c.logger.Debug("redmine response", "status", resp.StatusCode, "duration", time.Since(start), "body_bytes", buf.Len())
+		}()
+	}
+
+	if resp.StatusCode != expected {
+		var er errorsResult
+		json.NewDecoder(body).Decode(&er)
+		if resp.StatusCode == http.StatusUnprocessableEntity && len(er.Errors) > 0 {
+			return resp.StatusCode, &ValidationError{Errors: er.Errors}
+		}
+		er.Errors = append(er.Errors, fmt.Sprintf("unexpected status code %d (expected: %d, url: %s, method: %s)", resp.StatusCode, expected, req.URL, req.Method))
+
+		return resp.StatusCode, fmt.Errorf("%s", strings.Join(er.Errors, "\n"))
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("json decode error: %w", err)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+// maxAttempts is the number of times a rate limited request is sent.
+const maxAttempts = 3
+
+// maxRateLimitWait is the maximum time to wait for a rate limit to reset.
+const maxRateLimitWait = 5 * time.Minute
+
+// handleRateLimit returns the time to wait before retrying a request answered with 429.
+// The Retry-After header may contain seconds or an HTTP date, one second is used if it is missing.
+func handleRateLimit(resp *http.Response) time.Duration {
+	wait := time.Second
+
+	header := resp.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(header); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(header); err == nil {
+		wait = time.Until(date)
+	}
+
+	return min(max(wait, 0), maxRateLimitWait)
+}
+
+// get requests path and decodes the JSON response into out.
+func (c *Client) get(path string, query url.Values, out any) (int, error) {
+	req, err := c.newRequest(http.MethodGet, path, query, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.do(req, out, http.StatusOK)
+}
+
+// send sends in as JSON body with the given method and decodes the JSON response into out.
+func (c *Client) send(method, path string, in, out any, expected int) (int, error) {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := c.newRequest(method, path, nil, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.do(req, out, expected)
+}
+
+// pageLimit is the number of objects requested per page.
+const pageLimit = 100
+
+// getAll requests all pages of a paginated collection and returns the objects stored under key.
+func getAll[T any](c *Client, path string, query url.Values, key string) ([]T, int, error) {
+	return getFirst[T](c, path, query, key, -1)
+}
+
+// getFirst requests the pages of a paginated collection until max objects stored under key
+// are read. A negative max reads all pages.
+func getFirst[T any](c *Client, path string, query url.Values, key string, max int) ([]T, int, error) {
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+
+	all := make([]T, 0)
+	// without a request, e.g. for max 0, there is nothing which could fail
+	code := http.StatusOK
+	for offset := 0; max < 0 || offset < max; offset += pageLimit {
+		limit := pageLimit
+		if max >= 0 {
+			limit = min(limit, max-offset)
+		}
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("offset", strconv.Itoa(offset))
+
+		var page map[string]json.RawMessage
+		var err error
+		code, err = c.get(path, q, &page)
+		if err != nil {
+			return nil, code, err
+		}
+
+		var items []T
+		if err := json.Unmarshal(page[key], &items); err != nil {
+			return nil, code, fmt.Errorf("json decode error: %w", err)
+		}
+		all = append(all, items...)
+
+		var total int
+		if raw, ok := page["total_count"]; ok {
+			if err := json.Unmarshal(raw, &total); err != nil {
+				return nil, code, fmt.Errorf("json decode error: %w", err)
+			}
+		}
+		if len(items) == 0 || offset+len(items) >= total {
+			return all, code, nil
+		}
+	}
+
+	return all[:min(len(all), max)], code, nil
+}
@@ -0,0 +1,122 @@
+package redmine
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// inFlightHandler answers every request after delay and records the maximum of concurrent requests.
+type inFlightHandler struct {
+	delay    time.Duration
+	current  atomic.Int64
+	maxCount atomic.Int64
+}
+
+func (h *inFlightHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := h.current.Add(1)
+	defer h.current.Add(-1)
+	for {
+		m := h.maxCount.Load()
+		if n <= m || h.maxCount.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	time.Sleep(h.delay)
+	writeJSON(w, http.StatusOK, map[string]any{"issue": map[string]any{"id": 1}})
+}
+
+func TestMaxConnsCapsInFlightRequests(t *testing.T) {
+	handler := &inFlightHandler{delay: 20 * time.Millisecond}
+	c := newTestClient(t, handler, WithMaxConns(2))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetIssue(1); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := handler.maxCount.Load(); got > 2 {
+		t.Errorf("%d requests in flight, want at most 2", got)
+	}
+}
+
+func BenchmarkConcurrentGetIssue(b *testing.B) {
+	handler := &inFlightHandler{delay: time.Millisecond}
+	c := newTestClient(b, handler)
+
+	// more goroutines than connections, max-in-flight stays at the connection limit
+	b.SetParallelism(4 * defaultMaxConns)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.GetIssue(1); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+	b.ReportMetric(float64(handler.maxCount.Load()), "max-in-flight")
+}
+
+func TestRetryOnTooManyRequests(t *testing.T) {
+	var attempts []timeEntryCreate
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var create timeEntryCreate
+		if err := json.NewDecoder(r.Body).Decode(&create); err != nil {
+			t.Errorf("attempt %d: %v", len(attempts)+1, err)
+		}
+		attempts = append(attempts, create)
+
+		if len(attempts) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]any{"time_entry": map[string]any{"id": 42}})
+	}))
+
+	id, err := c.CreateTimeEntry(TimeEntry{
+		IssueIDs:   []string{"#123"},
+		Start:      time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		Duration:   2,
+		Comment:    "retried",
+		ActivityID: "9",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 {
+		t.Errorf("id = %d, want the id of the second response", id)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("sent %d requests, want 2", len(attempts))
+	}
+	if attempts[1].TimeEntry.Comments != "retried" {
+		t.Errorf("retry sent %+v, want the original body", attempts[1].TimeEntry)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+
+	if _, err := c.GetIssue(1); err == nil {
+		t.Error("GetIssue succeeded although every request was rate limited")
+	}
+	if attempts != maxAttempts {
+		t.Errorf("sent %d requests, want %d", attempts, maxAttempts)
+	}
+}
@@ -0,0 +1,35 @@
+package redmine
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	redmine "github.com/nixys/nxs-go-redmine/v5"
+)
+
+// NewsObject is a news item of a project.
+// The redmine library does not cover the news API.
+type NewsObject struct {
+	ID          int64          `json:"id"`
+	Project     redmine.IDName `json:"project"`
+	Author      redmine.IDName `json:"author"`
+	Title       string         `json:"title"`
+	Summary     string         `json:"summary"`
+	Description string         `json:"description"`
+	CreatedOn   string         `json:"created_on"`
+}
+
+// GetNewsItems returns up to limit news items of the project, newest first.
+// A negative limit returns all news items.
+func (c *Client) GetNewsItems(projectID string, limit int) ([]NewsObject, error) {
+	news, code, err := getFirst[NewsObject](c, "/projects/"+url.PathEscape(projectID)+"/news.json", nil, "news", limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting news of project %s: %w", projectID, err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error getting news of project %s: %d", projectID, code)
+	}
+
+	return news, nil
+}
@@ -0,0 +1,47 @@
+package redmine
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetNewsItemsLimit(t *testing.T) {
+	const total = 250
+	var requested atomic.Int64
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		news := make([]NewsObject, 0)
+		for id := offset + 1; id <= min(offset+limit, total); id++ {
+			news = append(news, NewsObject{ID: int64(id)})
+		}
+		requested.Add(int64(len(news)))
+		writeJSON(w, http.StatusOK, map[string]any{"news": news, "total_count": total})
+	}))
+
+	tests := []struct {
+		limit     int
+		want      int
+		requested int64
+	}{
+		{limit: 5, want: 5, requested: 5},
+		{limit: 120, want: 120, requested: 120},
+		{limit: 0, want: 0, requested: 0},
+		{limit: -1, want: total, requested: total},
+	}
+	for _, tt := range tests {
+		requested.Store(0)
+		news, err := c.GetNewsItems("app", tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(news) != tt.want {
+			t.Errorf("limit %d: got %d news items, want %d", tt.limit, len(news), tt.want)
+		}
+		if got := requested.Load(); got != tt.requested {
+			t.Errorf("limit %d: fetched %d news items, want %d", tt.limit, got, tt.requested)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package redmine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrUnauthorized is returned if redmine rejects the API key.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Ping checks that redmine is reachable and accepts the API key. Unlike GetCurrentUser
+// the response is discarded and the request is neither cached nor retried,
+// so Ping returns once ctx is done at the latest.
+func (c *Client) Ping(ctx context.Context) error {
+	req, err := c.newRequest(http.MethodGet, "/users/current.json", nil, nil)
+	if err != nil {
+		return fmt.Errorf("error pinging redmine: %w", err)
+	}
+
+	resp, err := c.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("error pinging redmine: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %d", ErrUnauthorized, resp.StatusCode)
+	default:
+		return fmt.Errorf("error pinging redmine: unexpected status code %d", resp.StatusCode)
+	}
+}
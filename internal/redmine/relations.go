@@ -0,0 +1,86 @@
+package redmine
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	redmine "github.com/nixys/nxs-go-redmine/v5"
+	"github.com/sanity-io/litter"
+)
+
+// RelationType is the type of a relation between two issues.
+type RelationType string
+
+const (
+	RelationRelates    RelationType = "relates"
+	RelationDuplicates RelationType = "duplicates"
+	RelationDuplicated RelationType = "duplicated"
+	RelationBlocks     RelationType = "blocks"
+	RelationBlocked    RelationType = "blocked"
+	RelationPrecedes   RelationType = "precedes"
+	RelationFollows    RelationType = "follows"
+	RelationCopiedTo   RelationType = "copied_to"
+	RelationCopiedFrom RelationType = "copied_from"
+)
+
+// RelationTypes are all relation types supported by redmine.
+var RelationTypes = []RelationType{
+	RelationRelates,
+	RelationDuplicates,
+	RelationDuplicated,
+	RelationBlocks,
+	RelationBlocked,
+	RelationPrecedes,
+	RelationFollows,
+	RelationCopiedTo,
+	RelationCopiedFrom,
+}
+
+// GetIssueRelations returns the relations of the issue with the given id.
+func (c *Client) GetIssueRelations(id int64) ([]redmine.IssueRelationObject, error) {
+	var result struct {
+		Relations []redmine.IssueRelationObject `json:"relations"`
+	}
+	code, err := c.get("/issues/"+strconv.FormatInt(id, 10)+"/relations.json", nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error getting relations of issue %d: %w", id, err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error getting relations of issue %d: %d", id, code)
+	}
+
+	return result.Relations, nil
+}
+
+// CreateRelation relates the issue fromID to the issue toID, e.g. fromID blocks toID.
+func (c *Client) CreateRelation(fromID, toID int64, relationType RelationType) error {
+	if !slices.Contains(RelationTypes, relationType) {
+		return fmt.Errorf("unknown relation type %s", relationType)
+	}
+
+	payload := struct {
+		Relation struct {
+			IssueToID    int64        `json:"issue_to_id"`
+			RelationType RelationType `json:"relation_type"`
+		} `json:"relation"`
+	}{}
+	payload.Relation.IssueToID = toID
+	payload.Relation.RelationType = relationType
+
+	if c.Dry {
+		litter.Dump(payload)
+		return nil
+	}
+
+	code, err := c.send(http.MethodPost, "/issues/"+strconv.FormatInt(fromID, 10)+"/relations.json", payload, nil, http.StatusCreated)
+	if code == http.StatusForbidden {
+		return fmt.Errorf("access forbidden on %d: %d", fromID, code)
+	}
+	if err != nil {
+		return fmt.Errorf("error relating issue %d to %d: %w", fromID, toID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package redmine
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	redmine "github.com/nixys/nxs-go-redmine/v5"
+)
+
+// TimeReport summarizes the spent time of a project.
+type TimeReport struct {
+	TotalHours float64
+	ByUser     map[string]float64
+	ByIssue    map[int64]float64
+}
+
+// ListTimeEntries returns all time entries of the project spent between from and to (inclusive).
+func (c *Client) ListTimeEntries(projectID string, from, to time.Time) ([]redmine.TimeEntryObject, error) {
+	filters := url.Values{
+		"project_id": {projectID},
+		"from":       {from.Format("2006-01-02")},
+		"to":         {to.Format("2006-01-02")},
+	}
+
+	entries, code, err := getAll[redmine.TimeEntryObject](c, "/time_entries.json", filters, "time_entries")
+	if err != nil {
+		return nil, fmt.Errorf("error listing time entries of project %s: %w", projectID, err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error listing time entries of project %s: %d", projectID, code)
+	}
+
+	return entries, nil
+}
+
+// GetSpentTimeReport aggregates the hours logged in the project between from and to
+// per user and per issue. Time entries without an issue are only part of TotalHours.
+func (c *Client) GetSpentTimeReport(projectID string, from, to time.Time) (*TimeReport, error) {
+	entries, err := c.ListTimeEntries(projectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TimeReport{
+		ByUser:  make(map[string]float64),
+		ByIssue: make(map[int64]float64),
+	}
+	for _, entry := range entries {
+		report.TotalHours += entry.Hours
+		report.ByUser[entry.User.Name] += entry.Hours
+		if entry.Issue.ID != 0 {
+			report.ByIssue[entry.Issue.ID] += entry.Hours
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,75 @@
+package redmine
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	redmine "github.com/nixys/nxs-go-redmine/v5"
+	"github.com/sanity-io/litter"
+)
+
+// VersionObject is a version (milestone) of a project.
+// The redmine library does not cover the versions API.
+type VersionObject struct {
+	ID          int64          `json:"id"`
+	Project     redmine.IDName `json:"project"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Status      string         `json:"status"`
+	DueDate     string         `json:"due_date"`
+	Sharing     string         `json:"sharing"`
+	CreatedOn   string         `json:"created_on"`
+	UpdatedOn   string         `json:"updated_on"`
+}
+
+// versionsPath returns the API path of the versions of the project.
+func versionsPath(projectID string) string {
+	return "/projects/" + url.PathEscape(projectID) + "/versions.json"
+}
+
+// GetVersions returns the versions of the project, including the ones shared by other projects.
+func (c *Client) GetVersions(projectID string) ([]VersionObject, error) {
+	var result struct {
+		Versions []VersionObject `json:"versions"`
+	}
+	code, err := c.get(versionsPath(projectID), nil, &result)
+	if err != nil {
+		return nil, fmt.Errorf("error getting versions of project %s: %w", projectID, err)
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("error getting versions of project %s: %d", projectID, code)
+	}
+
+	return result.Versions, nil
+}
+
+// CreateVersion creates the version with the given name in the project.
+// Description and dueDate (2006-01-02) are optional.
+func (c *Client) CreateVersion(projectID, name, description, dueDate string) error {
+	payload := struct {
+		Version struct {
+			Name        string `json:"name"`
+			Description string `json:"description,omitempty"`
+			DueDate     string `json:"due_date,omitempty"`
+		} `json:"version"`
+	}{}
+	payload.Version.Name = name
+	payload.Version.Description = description
+	payload.Version.DueDate = dueDate
+
+	if c.Dry {
+		litter.Dump(payload)
+		return nil
+	}
+
+	code, err := c.send(http.MethodPost, versionsPath(projectID), payload, nil, http.StatusCreated)
+	if code == http.StatusForbidden {
+		return fmt.Errorf("access forbidden on versions of project %s: %d", projectID, code)
+	}
+	if err != nil {
+		return fmt.Errorf("error creating version %s in project %s: %w", name, projectID, err)
+	}
+
+	return nil
+}
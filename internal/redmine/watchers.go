@@ -0,0 +1,77 @@
+package redmine
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	redmine "github.com/nixys/nxs-go-redmine/v5"
+	"github.com/sanity-io/litter"
+)
+
+// ErrAlreadyWatching is returned if the user already watches the issue.
+var ErrAlreadyWatching = errors.New("already watching")
+
+// ListWatchers returns the watchers of the issue with the given id.
+// Redmine only includes the ID and the name of the watching users.
+func (c *Client) ListWatchers(issueID int64) ([]redmine.IDName, error) {
+	var result struct {
+		Issue redmine.IssueObject `json:"issue"`
+	}
+	code, err := c.get("/issues/"+strconv.FormatInt(issueID, 10)+".json", url.Values{"include": {string(redmine.IssueIncludeWatchers)}}, &result)
+	if code == http.StatusForbidden {
+		return nil, fmt.Errorf("access forbidden on %d: %d", issueID, code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting watchers of issue %d: %w", issueID, err)
+	}
+	if result.Issue.Watchers == nil {
+		return nil, nil
+	}
+
+	return *result.Issue.Watchers, nil
+}
+
+// AddWatcher adds the user with the given id to the watchers of the issue.
+// ErrAlreadyWatching is returned if redmine rejects the user as duplicate,
+// other validation errors are returned as *ValidationError.
+func (c *Client) AddWatcher(issueID int64, userID int64) error {
+	payload := struct {
+		UserID int64 `json:"user_id"`
+	}{
+		UserID: userID,
+	}
+
+	if c.Dry {
+		litter.Dump(payload)
+		return nil
+	}
+
+	code, err := c.send(http.MethodPost, "/issues/"+strconv.FormatInt(issueID, 10)+"/watchers.json", payload, nil, http.StatusNoContent)
+	var validation *ValidationError
+	switch {
+	case errors.As(err, &validation) && alreadyWatching(validation):
+		return fmt.Errorf("%w: issue %d", ErrAlreadyWatching, issueID)
+	case code == http.StatusForbidden:
+		return fmt.Errorf("access forbidden on %d: %d", issueID, code)
+	case err != nil:
+		return fmt.Errorf("error adding watcher to issue %d: %w", issueID, err)
+	}
+
+	return nil
+}
+
+// alreadyWatching reports whether redmine rejected a watcher because the user
+// already watches the issue, e.g. "User has already been taken".
+func alreadyWatching(err *ValidationError) bool {
+	for _, msg := range err.Errors {
+		if strings.Contains(strings.ToLower(msg), "already") {
+			return true
+		}
+	}
+
+	return false
+}
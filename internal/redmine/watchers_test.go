@@ -0,0 +1,41 @@
+package redmine
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestAddWatcherErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		already  bool
+	}{
+		{"duplicate", []string{"User has already been taken"}, true},
+		{"invalid user", []string{"User is invalid"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, http.StatusUnprocessableEntity, errorsResult{Errors: tt.messages})
+			}))
+
+			err := c.AddWatcher(1, 2)
+			if got := errors.Is(err, ErrAlreadyWatching); got != tt.already {
+				t.Fatalf("AddWatcher() = %v, already watching %t, want %t", err, got, tt.already)
+			}
+			if tt.already {
+				return
+			}
+
+			// other validation errors keep the messages of redmine
+			var validation *ValidationError
+			if !errors.As(err, &validation) || !slices.Equal(validation.Errors, tt.messages) {
+				t.Errorf("AddWatcher() = %v, want a *ValidationError with %v", err, tt.messages)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package redmine
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	redmine "github.com/nixys/nxs-go-redmine/v5"
+	"github.com/sanity-io/litter"
+)
+
+// wikiPath returns the API path of the wiki page with the given title.
+func wikiPath(projectID, title string) string {
+	return "/projects/" + url.PathEscape(projectID) + "/wiki/" + url.PathEscape(title) + ".json"
+}
+
+// CreateWikiPage creates the wiki page with the given title in the project.
+// Redmine replaces the content of an existing page and answers with 204 No Content instead of 201 Created.
+func (c *Client) CreateWikiPage(projectID, title, content string) error {
+	payload := redmine.WikiCreateObject{
+		Text: content,
+	}
+
+	if c.Dry {
+		litter.Dump(payload)
+		return nil
+	}
+
+	code, err := c.send(http.MethodPut, wikiPath(projectID, title), redmine.WikiCreate{
+		WikiPage: payload,
+	}, nil, http.StatusCreated)
+	if code == http.StatusForbidden {
+		return fmt.Errorf("access forbidden on wiki of project %s: %d", projectID, code)
+	}
+	if code == http.StatusNoContent {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error creating wiki page %s in project %s: %w", title, projectID, err)
+	}
+	if code != http.StatusCreated {
+		return fmt.Errorf("unexpected code creating wiki page %s in project %s: %d", title, projectID, code)
+	}
+
+	return nil
+}
+
+// UpdateWikiPage replaces the content of the wiki page with the given title in the project.
+// The comment describes the change in the page history.
+func (c *Client) UpdateWikiPage(projectID, title, content, comment string) error {
+	payload := redmine.WikiUpdateObject{
+		Text: content,
+	}
+	if comment != "" {
+		payload.Comments = &comment
+	}
+
+	if c.Dry {
+		litter.Dump(payload)
+		return nil
+	}
+
+	code, err := c.send(http.MethodPut, wikiPath(projectID, title), redmine.WikiUpdate{
+		WikiPage: payload,
+	}, nil, http.StatusNoContent)
+	if code == http.StatusForbidden {
+		return fmt.Errorf("access forbidden on wiki of project %s: %d", projectID, code)
+	}
+	if err != nil {
+		return fmt.Errorf("error updating wiki page %s in project %s: %w", title, projectID, err)
+	}
+	if code != http.StatusNoContent {
+		return fmt.Errorf("unexpected code updating wiki page %s in project %s: %d", title, projectID, code)
+	}
+
+	return nil
+}
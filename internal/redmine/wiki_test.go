@@ -0,0 +1,36 @@
+package redmine
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCreateWikiPageStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    int
+		wantErr bool
+	}{
+		{"created", http.StatusCreated, false},
+		{"existing page", http.StatusNoContent, false},
+		{"forbidden", http.StatusForbidden, true},
+		{"not found", http.StatusNotFound, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.EscapedPath() != "/projects/app/wiki/Release%20Notes.json" {
+					http.NotFound(w, r)
+					return
+				}
+				w.WriteHeader(tt.code)
+			}))
+
+			err := c.CreateWikiPage("app", "Release Notes", "content")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateWikiPage() = %v, want error %t", err, tt.wantErr)
+			}
+		})
+	}
+}